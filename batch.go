@@ -0,0 +1,98 @@
+package i18n
+
+import (
+	"context"
+	"sync"
+)
+
+const defaultBatchConcurrency = 4
+
+// GetTexts retrieves the texts of the given keys in a given language i18n
+// package. The options are handled and the packages of the namespace stack
+// are looked up only once for all the keys. The texts of the found keys are
+// returned along with a `BatchError` keyed by the failed keys if any.
+func (c *client) GetTexts(ctx context.Context, lang string, keys []string, opts ...Option) (map[string]string, error) {
+	o := op.get()
+	defer op.put(o)
+	layers, err := c.lookupLayers(ctx, o, lang, opts...)
+	if err != nil {
+		return nil, c.wrapError(o, lang, "", TextResult{}, err)
+	}
+	texts := make(map[string]string, len(keys))
+	errs := make(map[string]error)
+	for _, key := range keys {
+		res, err := c.resolveText(ctx, o, layers, lang, key)
+		if err != nil {
+			errs[key] = c.wrapError(o, lang, key, res, err)
+			continue
+		}
+		texts[key] = res.Text
+	}
+	if len(errs) != 0 {
+		return texts, &BatchError{Errors: errs}
+	}
+	return texts, nil
+}
+
+// GetPackages returns the copies of the packages of the given languages, the
+// missing ones are fetched concurrently with the parallelism set by
+// `WithBatchConcurrency`. The fetching is deduplicated with the other requests
+// of the same package, and the languages not started when the context is done
+// fail with its error. The loaded packages are returned along with a
+// `BatchError` keyed by the failed languages if any.
+func (c *client) GetPackages(ctx context.Context, langs []string, opts ...Option) (map[string]*Package, error) {
+	o := op.get()
+	for _, f := range c.loadOptions() {
+		f(o)
+	}
+	for _, f := range opts {
+		f(o)
+	}
+	defer op.put(o)
+	concurrency := o.batchConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	pkgs := make(map[string]*Package, len(langs))
+	errs := make(map[string]error)
+	sem := make(chan struct{}, concurrency)
+	for i, lang := range langs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if err := ctx.Err(); err != nil {
+			mu.Lock()
+			for _, lang := range langs[i:] {
+				errs[lang] = c.wrapError(o, lang, "", TextResult{}, err)
+			}
+			mu.Unlock()
+			break
+		}
+		wg.Add(1)
+		go func(lang string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			o := op.get()
+			defer op.put(o)
+			pkg, err := c.getPackage(ctx, o, lang, opts...)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[lang] = c.wrapError(o, lang, "", TextResult{}, err)
+				return
+			}
+			pkgs[lang] = c.applyOverrides(o, pkg.Clone())
+		}(lang)
+	}
+	wg.Wait()
+	if len(errs) != 0 {
+		return pkgs, &BatchError{Errors: errs}
+	}
+	return pkgs, nil
+}
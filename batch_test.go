@@ -0,0 +1,112 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countFetcher struct {
+	mockFetcher
+	mu      sync.Mutex
+	calls   map[string]int
+	running int32
+	peak    int32
+	release chan struct{}
+}
+
+func (f *countFetcher) Fetch(ctx context.Context, pid, nid int64, lang string, opts ...Option) (*Package, error) {
+	n := atomic.AddInt32(&f.running, 1)
+	defer atomic.AddInt32(&f.running, -1)
+	for {
+		peak := atomic.LoadInt32(&f.peak)
+		if n <= peak || atomic.CompareAndSwapInt32(&f.peak, peak, n) {
+			break
+		}
+	}
+	f.mu.Lock()
+	f.calls[lang]++
+	f.mu.Unlock()
+	if f.release != nil {
+		<-f.release
+	}
+	time.Sleep(20 * time.Millisecond)
+	return f.mockFetcher.Fetch(ctx, pid, nid, lang, opts...)
+}
+
+func TestClientGetTexts(t *testing.T) {
+	c, err := NewClient(1, 2, WithFetcher(&mockFetcher{}))
+	assert.NotNil(t, c)
+	assert.Nil(t, err)
+	defer c.Shutdown(context.TODO())
+
+	texts, err := c.GetTexts(context.TODO(), "INVALID", []string{"key1"})
+	assert.True(t, errors.Is(err, ErrBackToSourceFailed))
+	assert.Nil(t, texts)
+
+	texts, err = c.GetTexts(context.TODO(), "en", []string{"key1", "key2", "key5", "not-exist-key"},
+		WithPluralCount(1),
+		WithArguments(map[string]interface{}{"farm": "ByteDance"}))
+	errs := err.(*BatchError).Errors
+	assert.Equal(t, 3, len(errs))
+	assert.True(t, errors.Is(errs["key1"], ErrInvalidICUFormat))
+	assert.True(t, errors.Is(errs["key2"], ErrInvalidICUFormat))
+	assert.True(t, errors.Is(errs["not-exist-key"], ErrKeyNotExist))
+	assert.Equal(t, map[string]string{"key5": "I have 1 apple from ByteDance."}, texts)
+
+	texts, err = c.GetTexts(context.TODO(), "en", []string{"key1", "key2"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"key1": "v1", "key2": "v2"}, texts)
+}
+
+func TestClientGetPackages(t *testing.T) {
+	fetcher := &countFetcher{calls: make(map[string]int), release: make(chan struct{})}
+	c, err := NewClient(1, 2, WithFetcher(fetcher), WithBatchConcurrency(2))
+	assert.NotNil(t, c)
+	assert.Nil(t, err)
+	defer c.Shutdown(context.TODO())
+
+	langs := []string{"en", "de", "ja", "fr", "INVALID"}
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pkgs, err := c.GetPackages(context.TODO(), langs)
+			errs := err.(*BatchError).Errors
+			assert.Equal(t, 1, len(errs))
+			assert.True(t, errors.Is(errs["INVALID"], ErrBackToSourceFailed))
+			assert.Equal(t, 4, len(pkgs))
+			for lang, pkg := range pkgs {
+				assert.Equal(t, lang, pkg.Language)
+			}
+		}()
+	}
+	// All the callers wait for the blocked fetching of the same packages.
+	time.Sleep(50 * time.Millisecond)
+	close(fetcher.release)
+	wg.Wait()
+	assert.True(t, atomic.LoadInt32(&fetcher.peak) <= 6, fetcher.peak)
+	for _, lang := range langs[:4] {
+		assert.Equal(t, 1, fetcher.calls[lang], lang)
+	}
+
+	pkgs, err := c.GetPackages(context.TODO(), []string{"en", "de"})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(pkgs))
+
+	// Test the languages not started when the context is done.
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	pkgs, err = c.GetPackages(ctx, []string{"en", "ko"})
+	assert.Equal(t, 0, len(pkgs))
+	errs := err.(*BatchError).Errors
+	assert.Equal(t, 2, len(errs))
+	assert.True(t, errors.Is(errs["en"], context.Canceled))
+	assert.True(t, errors.Is(errs["ko"], context.Canceled))
+}
@@ -0,0 +1,48 @@
+package i18n
+
+import (
+	"golang.org/x/text/language"
+)
+
+// Direction is the writing direction of a language, which can be used as the
+// `dir` attribute in HTML.
+type Direction string
+
+const (
+	// DirectionLTR is the left-to-right direction.
+	DirectionLTR Direction = "ltr"
+	// DirectionRTL is the right-to-left direction.
+	DirectionRTL Direction = "rtl"
+)
+
+const (
+	// firstStrongIsolate and popDirectionalIsolate isolate the direction of an
+	// interpolated value from the surrounding text.
+	firstStrongIsolate    = "\u2068"
+	popDirectionalIsolate = "\u2069"
+)
+
+// rtlScripts are the scripts written from right to left.
+var rtlScripts = map[string]bool{
+	"Adlm": true, "Arab": true, "Hebr": true, "Mand": true, "Mend": true, "Nkoo": true,
+	"Rohg": true, "Samr": true, "Syrc": true, "Thaa": true, "Yezi": true,
+}
+
+// LanguageDirection returns the writing direction of the language by its
+// script, which is inferred if it is not given, e.g. `ar` and `he` are RTL
+// while `az-Arab` is RTL and `az` is LTR.
+func LanguageDirection(lang string) Direction {
+	script, _ := language.Make(lang).Script()
+	if rtlScripts[script.String()] {
+		return DirectionRTL
+	}
+	return DirectionLTR
+}
+
+// isolate wraps the text in the directional isolates if it is not empty.
+func isolate(text string) string {
+	if len(text) == 0 {
+		return text
+	}
+	return firstStrongIsolate + text + popDirectionalIsolate
+}
@@ -0,0 +1,51 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLanguageDirection(t *testing.T) {
+	for _, lang := range []string{"ar", "ar-EG", "he", "iw", "fa", "ur", "yi", "dv", "az-Arab", PseudoBidi} {
+		assert.Equal(t, DirectionRTL, LanguageDirection(lang), lang)
+	}
+	for _, lang := range []string{"en", "zh-Hans", "ja", "az", "ru", "", "INVALID"} {
+		assert.Equal(t, DirectionLTR, LanguageDirection(lang), lang)
+	}
+}
+
+func TestBidiIsolation(t *testing.T) {
+	c, err := NewClient(1, 2, WithFetcher(&mockFetcher{}), WithOverrides(NewStaticOverrides(Override{Texts: map[string]string{
+		"welcome": "مرحبا {name}، لديك {n, number} رسائل{none}",
+		"inbox":   "{n, plural, one {رسالة واحدة} other {# رسائل}} من {name}",
+	}})))
+	assert.Nil(t, err)
+	defer c.Shutdown(context.TODO())
+
+	args := WithArguments(map[string]interface{}{"name": "Tom", "n": 3})
+	text, err := c.GetText(context.TODO(), "ar", "welcome", args, WithBidiIsolation(true))
+	assert.Nil(t, err)
+	assert.Equal(t, "مرحبا \u2068Tom\u2069، لديك \u2068٣\u2069 رسائل", text)
+	text, err = c.GetText(context.TODO(), "ar", "welcome", args)
+	assert.Nil(t, err)
+	assert.Equal(t, "مرحبا Tom، لديك ٣ رسائل", text)
+	// The LTR languages are never isolated.
+	text, err = c.GetText(context.TODO(), "en", "welcome", args, WithBidiIsolation(true))
+	assert.Nil(t, err)
+	assert.Equal(t, "مرحبا Tom، لديك 3 رسائل", text)
+
+	// The count of a plural is isolated as well.
+	text, err = c.GetText(context.TODO(), "ar", "inbox", args, WithBidiIsolation(true))
+	assert.Nil(t, err)
+	assert.Equal(t, "\u2068٣\u2069 رسائل من \u2068Tom\u2069", text)
+	text, err = c.GetText(context.TODO(), "ar", "inbox", WithPluralCount(5), WithBidiIsolation(true),
+		WithLeftDelimiter("[["), WithRightDelimiter("]]"))
+	assert.Nil(t, err)
+	assert.Equal(t, "\u2068٥\u2069 رسائل من {name}", text)
+
+	rich, err := c.GetRichText(context.TODO(), "he", "welcome", nil, args, WithBidiIsolation(true))
+	assert.Nil(t, err)
+	assert.Equal(t, "مرحبا \u2068Tom\u2069، لديك \u20683\u2069 رسائل", rich.String())
+}
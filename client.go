@@ -7,18 +7,48 @@ import (
 
 // Client provides the essential APIs of the i18n client SDK.
 type Client interface {
-	// GetPackage returns a single language i18n text package data as a whole.
+	// GetPackage returns a single language i18n text package data as a whole,
+	// which is a copy and can be modified by callers.
 	GetPackage(ctx context.Context, lang string, opts ...Option) (*Package, error)
+	// GetPackageView returns a read-only view of a single language i18n text
+	// package data without copying.
+	GetPackageView(ctx context.Context, lang string, opts ...Option) (PackageView, error)
 	// GetText returns the text of the given key in a single language i18n
 	// text package data as a string.
 	GetText(ctx context.Context, lang, key string, opts ...Option) (string, error)
+	// ExplainText returns the text of the given key as `GetText` along with
+	// where it comes from, such as the namespace and package version.
+	ExplainText(ctx context.Context, lang, key string, opts ...Option) (*TextResult, error)
+	// GetRichText returns the text of the given key as `GetText` parsed into
+	// the text and tag nodes, where only the given tags are allowed.
+	GetRichText(ctx context.Context, lang, key string, tags []string, opts ...Option) (RichText, error)
+	// GetTexts returns the texts of the given keys in a single language i18n
+	// text package data, along with a `BatchError` of the failed keys if any.
+	GetTexts(ctx context.Context, lang string, keys []string, opts ...Option) (map[string]string, error)
+	// GetPackages returns the i18n text package data of the given languages,
+	// along with a `BatchError` of the failed languages if any.
+	GetPackages(ctx context.Context, langs []string, opts ...Option) (map[string]*Package, error)
 	// AddOption allows users to add some global options in order to not set them
-	// in each request if they will not change frequently. It must not be called
-	// concurrently and the same option set later will overwrite the former one.
+	// in each request if they will not change frequently. It can be called
+	// concurrently at any time and the same option set later will overwrite the
+	// former one. The background refresher picks up the new refresh interval,
+	// cache duration, fetcher and logger in its next round. The HTTP timeout of
+	// the default fetcher and the options of a fetcher given by `WithFetcher`
+	// are fixed when it is created.
 	AddOption(opts ...Option)
+	// WaitReady blocks until the preload packages set by `WithPreload` are
+	// loaded, and returns a `BatchError` with the errors of each failed one.
+	WaitReady(ctx context.Context) error
+	// Ready returns nil if all the preload packages are loaded without blocking,
+	// which can be used in the readiness check of a health endpoint.
+	Ready() error
 	// Shutdown cleans the resources and exit gracefully, which should be called
-	// in a deferred function in the main routine.
-	Shutdown()
+	// in a deferred function in the main routine. It stops the remote fetching,
+	// cancels the in-flight requests, waits for the background refresher and
+	// flushes the facilities until the context is done. The cached packages
+	// are still served after shutdown. It is safe to be called concurrently
+	// and more than once.
+	Shutdown(ctx context.Context) error
 }
 
 // NewClient creates an instance of client which can be used by callers as a
@@ -27,45 +57,64 @@ func NewClient(pid, nid int64, opts ...Option) (*client, error) {
 	if pid <= 0 || nid <= 0 {
 		return nil, ErrInvalidParams
 	}
+	return newClient(pid, nid, opts...), nil
+}
+
+// newClient creates the client with the default facilities and starts the
+// background procedures, the project and namespace may be zero for the shared
+// client of a `Registry`.
+func newClient(pid, nid int64, opts ...Option) *client {
 	c := &client{
 		projectID:   pid,
 		namespaceID: nid,
-		options:     opts,
-		shutdownCh:  make(chan struct{}),
+		done:        make(chan struct{}),
 	}
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	options := append([]Option(nil), opts...)
 	o := option{}
 	for _, f := range opts {
 		f(&o)
 	}
 	if o.retryPolicy == nil {
 		o.retryPolicy = NewBackoffRetryPolicy(3, 4000, 500)
-		c.options = append(c.options, WithRetryPolicy(o.retryPolicy))
+		options = append(options, WithRetryPolicy(o.retryPolicy))
 	}
 	if o.logger == nil {
-		o.logger = DefaultLogger()
-		c.options = append(c.options, WithLogger(o.logger))
+		o.logger = withRedaction(DefaultLogger(), o.appKey, o.operator)
+		options = append(options, WithLogger(o.logger))
 	}
 	if o.metricer == nil {
 		o.metricer = DefaultMetricer()
-		c.options = append(c.options, WithMetricer(o.metricer))
+		options = append(options, WithMetricer(o.metricer))
 	}
 	if o.fetcher == nil {
-		o.fetcher = NewHttpFetcher(append([]Option{
+		f := NewHttpFetcher(append([]Option{
 			WithLogger(o.logger),
 			WithMetricer(o.metricer),
 			WithRetryPolicy(o.retryPolicy),
 		}, opts...)...)
-		c.options = append(c.options, WithFetcher(o.fetcher))
+		// The options of the client are passed to every fetch as the request
+		// options, so the interceptors and tracer must not run twice, and the
+		// credentials and scheme follow the options added by `AddOption`.
+		f.option.interceptors, f.option.tracer = nil, nil
+		f.option.appKey, f.option.operator, f.option.enableHTTPs = "", "", false
+		o.fetcher = f
+		options = append(options, WithFetcher(o.fetcher))
 	}
 	if int64(o.refreshInterval) < int64(time.Second) {
 		o.refreshInterval = defaultRefreshInterval
-		c.options = append(c.options, WithRefreshInterval(defaultRefreshInterval))
+		options = append(options, WithRefreshInterval(defaultRefreshInterval))
 	}
 	if int64(o.cacheDuration) < int64(time.Minute) {
 		o.cacheDuration = defaultCacheDuration
-		c.options = append(c.options, WithCacheDuration(defaultCacheDuration))
+		options = append(options, WithCacheDuration(defaultCacheDuration))
 	}
+	c.options.Store(options)
 
-	go c.refresher(context.Background())
-	return c, nil
+	c.ready = newReadiness(o.preload)
+	go c.ready.load(func(t PreloadTarget) error {
+		return c.preload(c.ctx, t)
+	})
+	go c.refresher(c.ctx)
+	return c
 }
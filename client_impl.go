@@ -1,17 +1,13 @@
 package i18n
 
 import (
-	"bytes"
 	"context"
-	"fmt"
-	"html/template"
-	"regexp"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
 	"golang.org/x/text/language"
 )
 
@@ -21,66 +17,322 @@ import (
 type client struct {
 	projectID   int64
 	namespaceID int64
-	options     []Option
+	options     atomic.Value // []Option, copy-on-write snapshot
 	mu          sync.RWMutex
 	data        sync.Map
+	pseudo      sync.Map // cache key of the pseudo locale -> *pseudoEntry
 	sf          Group
-	shutdownCh  chan struct{}
+	ready       *readiness
+	onRefresh   func(ctx context.Context) // called at the end of each refreshing
+
+	ctx       context.Context // canceled on shutdown to stop remote fetching
+	cancel    context.CancelFunc
+	closed    bool           // protected by mu
+	inflight  sync.WaitGroup // in-flight remote fetching
+	done      chan struct{}  // closed when the refresher exits
+	closeOnce sync.Once
+	flushOnce sync.Once
+	flushErr  error
 }
 
-// GetPackage returns a whole package of the given language.
+// GetPackage returns a copy of the whole package of the given language, so
+// modifying it does not affect the local cache.
 func (c *client) GetPackage(ctx context.Context, lang string, opts ...Option) (*Package, error) {
 	o := op.get()
 	defer op.put(o)
-	return c.getPackage(ctx, o, lang, opts...)
+	pkg, err := c.getPackage(ctx, o, lang, opts...)
+	if err != nil {
+		return nil, c.wrapError(o, lang, "", TextResult{}, err)
+	}
+	return c.applyOverrides(o, pkg.Clone()), nil
+}
+
+// GetPackageView returns a read-only view of the package of the given language
+// without copying, the overrides are not applied to the view.
+func (c *client) GetPackageView(ctx context.Context, lang string, opts ...Option) (PackageView, error) {
+	o := op.get()
+	defer op.put(o)
+	pkg, err := c.getPackage(ctx, o, lang, opts...)
+	if err != nil {
+		return PackageView{}, c.wrapError(o, lang, "", TextResult{}, err)
+	}
+	return PackageView{pkg}, nil
 }
 
 // GetText retrieves the text string of the given key in a given language i18n package.
 func (c *client) GetText(ctx context.Context, lang, key string, opts ...Option) (val string, err error) {
 	o := op.get()
 	defer op.put(o)
-	pkg, pkgErr := c.getPackage(ctx, o, lang, opts...)
-	if pkgErr != nil {
-		err = pkgErr
+	layers, err := c.lookupLayers(ctx, o, lang, opts...)
+	if err != nil {
+		return "", c.wrapError(o, lang, key, TextResult{}, err)
+	}
+	res, err := c.resolveText(ctx, o, layers, lang, key)
+	return res.Text, c.wrapError(o, lang, key, res, err)
+}
+
+// ExplainText retrieves the text as `GetText` and explains where it comes from.
+func (c *client) ExplainText(ctx context.Context, lang, key string, opts ...Option) (*TextResult, error) {
+	o := op.get()
+	defer op.put(o)
+	layers, err := c.lookupLayers(ctx, o, lang, opts...)
+	if err != nil {
+		return nil, c.wrapError(o, lang, key, TextResult{}, err)
+	}
+	res, err := c.resolveText(ctx, o, layers, lang, key)
+	if err != nil {
+		return nil, c.wrapError(o, lang, key, res, err)
+	}
+	return &res, nil
+}
+
+// resolveText looks up the key in the layers in order and formats the text
+// with the handled option.
+func (c *client) resolveText(ctx context.Context, o *option, layers []layer, lang, key string) (res TextResult, err error) {
+	res, err = c.resolveRaw(ctx, o, layers, lang, key)
+	if err != nil || res.Source == SourceMissing {
 		return
 	}
-	raw, ok := pkg.Data[key]
+	res.Text, err = c.format(ctx, o, res.Text, lang, key)
+	return
+}
+
+// resolveRaw looks up the key in the layers in order and returns the raw text.
+// The override texts take precedence over the package of each layer, and the
+// missing key is replaced by the default message or the missing key handler.
+func (c *client) resolveRaw(ctx context.Context, o *option, layers []layer, lang, key string) (res TextResult, err error) {
+	fetched := false
+	for _, l := range layers {
+		source := SourceOverride
+		raw, ok := "", false
+		if o.override != nil {
+			raw, ok = o.override.Overrides(o.projectID, l.namespaceID, o.env, lang)[key]
+		}
+		if !ok && l.pkg != nil {
+			source = SourceRemote
+			raw, ok = l.pkg.Data[key]
+		}
+		fetched = fetched || l.pkg != nil
+		if !ok {
+			continue
+		}
+		if len(o.namespaceStack) != 0 {
+			o.metricer.EmitCounter(clientLayerHitMetricsKey, 1, map[string]string{
+				"projectID":   strconv.FormatInt(o.projectID, 10),
+				"namespaceID": strconv.FormatInt(l.namespaceID, 10),
+				"layer":       strconv.Itoa(l.index),
+				"source":      source,
+			})
+		}
+		if source == SourceOverride {
+			c.overridden(o, l.namespaceID, lang, key)
+		}
+		res = TextResult{
+			Text:        raw,
+			Key:         key,
+			Language:    lang,
+			ProjectID:   o.projectID,
+			NamespaceID: l.namespaceID,
+			Env:         o.env,
+			Layer:       l.index,
+			Source:      source,
+		}
+		if l.pkg != nil {
+			res.Version, res.ReleaseVersion = l.pkg.Version, l.pkg.ReleaseVersion
+		}
+		return
+	}
+	if !fetched {
+		err = layers[0].err
+		return
+	}
+
+	logWith(o.logger, LevelWarn, "starling: text not existed", o.fields(Field{"key", key})...)
+	o.metricer.EmitCounter(clientKeyEmptyMetricsKey, 1, map[string]string{
+		"projectID":   strconv.FormatInt(o.projectID, 10),
+		"namespaceID": strconv.FormatInt(o.namespaceID, 10),
+		"language":    lang,
+		"env":         o.env,
+		"key":         key,
+	})
+	return c.resolveMissing(ctx, o, layers, lang, key)
+}
+
+// resolveMissing returns the default message or the replacement of the missing
+// key handler, or `ErrKeyNotExist` if there is neither.
+func (c *client) resolveMissing(ctx context.Context, o *option, layers []layer, lang, key string) (TextResult, error) {
+	if o.defaultMessage == nil && o.missingKeyHandler == nil {
+		return TextResult{}, ErrKeyNotExist
+	}
+	res := TextResult{
+		Key:         key,
+		Language:    lang,
+		ProjectID:   o.projectID,
+		NamespaceID: o.namespaceID,
+		Env:         o.env,
+	}
+	if pkg := layers[0].pkg; pkg != nil {
+		res.NamespaceID = layers[0].namespaceID
+		res.Version, res.ReleaseVersion = pkg.Version, pkg.ReleaseVersion
+	}
+	if o.defaultMessage != nil {
+		res.Text, res.Source = *o.defaultMessage, SourceDefault
+		return res, nil
+	}
+	text, ok := o.missingKeyHandler(ctx, res)
 	if !ok {
-		o.logger.Warn("[starling-client-go] text not existed: key=%v", key)
-		o.metricer.EmitCounter(clientKeyEmptyMetricsKey, 1, map[string]string{
-			"projectID":   strconv.FormatInt(o.projectID, 10),
-			"namespaceID": strconv.FormatInt(o.namespaceID, 10),
-			"language":    lang,
-			"env":         o.env,
-			"key":         key,
-		})
-		err = ErrKeyNotExist
+		return TextResult{}, ErrKeyNotExist
+	}
+	res.Text, res.Source = text, SourceMissing
+	return res, nil
+}
+
+// format renders the plurals, selects and arguments of the raw text in one
+// pass over the parsed message. The variables of the custom delimiters and the
+// malformed text without any plural are processed by `processVars` instead.
+func (c *client) format(ctx context.Context, o *option, raw, lang, key string) (val string, err error) {
+	nodes, err := parseFormat(o, raw)
+	if err != nil {
+		return "", err
+	}
+	if nodes != nil {
+		_, span := startSpan(ctx, o.tracer, spanRenderMessage, Field{"key", key})
+		val, _, err = renderMessage(nodes, lang, o, false)
+		endSpan(span, err)
 		return
 	}
 	val = raw
-	if o.pluralCount != nil {
-		val, err = c.processPlural(raw, lang, o.pluralDefaultLang, o.pluralCount)
+	if o.pluralCount != nil || choiceRegexp.MatchString(raw) {
+		_, span := startSpan(ctx, o.tracer, spanProcessPlural, Field{"key", key})
+		val, err = c.processPlural(raw, lang, o.pluralDefaultLang, o.pluralCount, o.bidiIsolation, o.arguments)
+		endSpan(span, err)
+		if err != nil {
+			return
+		}
 	}
 	if len(o.arguments) != 0 {
-		val, err = c.processVars(val, o.arguments, o.leftDelimiter, o.rightDelimiter)
+		_, span := startSpan(ctx, o.tracer, spanProcessVars, Field{"key", key})
+		val, err = c.processVars(val, lang, o.timeZone, o.bidiIsolation, o.arguments, o.leftDelimiter, o.rightDelimiter)
+		endSpan(span, err)
 	}
 	return
 }
 
-// AddOption implements the `Client` interface's method.
+// AddOption implements the `Client` interface's method. It replaces the
+// options with a new snapshot merged from the old one, so the readers are
+// never blocked and the snapshot never grows.
 func (c *client) AddOption(opts ...Option) {
-	c.options = append(c.options, opts...)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.options.Store(mergeOptions(c.loadOptions(), opts))
+}
+
+// WaitReady implements the `Client` interface's method.
+func (c *client) WaitReady(ctx context.Context) error {
+	return c.ready.wait(ctx)
+}
+
+// Ready implements the `Client` interface's method.
+func (c *client) Ready() error {
+	return c.ready.err()
 }
 
 // Shutdown cleans the resources and exits gracefully.
-func (c *client) Shutdown() {
-	if c.shutdownCh != nil {
-		close(c.shutdownCh)
-		c.shutdownCh = nil
+func (c *client) Shutdown(ctx context.Context) error {
+	c.closeOnce.Do(func() {
+		c.mu.Lock()
+		c.closed = true
+		c.mu.Unlock()
+		c.cancel()
+	})
+
+	waitCh := make(chan struct{})
+	go func() {
+		<-c.done
+		c.inflight.Wait()
+		close(waitCh)
+	}()
+	select {
+	case <-waitCh:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	c.flushOnce.Do(func() {
+		c.flushErr = c.flush(ctx)
+	})
+	return c.flushErr
+}
+
+// flush flushes the facilities which implement the `Flusher` interface and
+// calls the shutdown hooks, it returns the first error if any.
+func (c *client) flush(ctx context.Context) (err error) {
+	o := op.get()
+	defer op.put(o)
+	for _, f := range c.loadOptions() {
+		f(o)
 	}
+	for _, v := range []interface{}{o.fetcher, o.metricer, o.logger, o.tracer} {
+		if f, ok := v.(Flusher); ok {
+			if e := f.Flush(ctx); e != nil && err == nil {
+				err = e
+			}
+		}
+	}
+	for _, hook := range o.shutdownHooks {
+		if e := hook(ctx); e != nil && err == nil {
+			err = e
+		}
+	}
+	return
 }
 
-func (c *client) getPackage(ctx context.Context, o *option, lang string, opts ...Option) (data *Package, err error) {
+// isClosed reports whether the client is shutdown.
+func (c *client) isClosed() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.closed
+}
+
+// acquire registers an in-flight remote fetching and binds the context with
+// the client lifetime, it fails if the client is shutdown.
+func (c *client) acquire(ctx context.Context) (context.Context, func(), error) {
+	c.mu.RLock()
+	if c.closed {
+		c.mu.RUnlock()
+		return nil, nil, ErrClientClosed
+	}
+	c.inflight.Add(1)
+	c.mu.RUnlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-c.ctx.Done():
+			cancel()
+		case <-stop:
+		}
+	}()
+	return ctx, func() {
+		close(stop)
+		cancel()
+		c.inflight.Done()
+	}, nil
+}
+
+func (c *client) getPackage(ctx context.Context, o *option, lang string, opts ...Option) (*Package, error) {
+	if isPseudoLocale(lang) {
+		var cfg *PseudoConfig
+		peekOptions(c.loadOptions(), opts, func(o *option) { cfg = o.pseudo })
+		if cfg != nil {
+			return c.getPseudoPackage(ctx, o, lang, *cfg, opts...)
+		}
+	}
+	return c.loadPackage(ctx, o, lang, opts...)
+}
+
+// loadPackage returns the package from the local cache or fetches it.
+func (c *client) loadPackage(ctx context.Context, o *option, lang string, opts ...Option) (data *Package, err error) {
 	defer func() {
 		if err != nil {
 			o.metricer.EmitCounter(clientPackageEmptyMetricsKey, 1, map[string]string{"error": err.Error()})
@@ -89,34 +341,59 @@ func (c *client) getPackage(ctx context.Context, o *option, lang string, opts ..
 	var optArr []Option
 	optArr, err = c.handleOptions(o, lang, opts...)
 	if err != nil {
-		o.logger.Warn("[starling-client-go] handle options failed: %v %v", o, err)
+		logWith(o.logger, LevelWarn, "starling: handle options failed", o.fields(Field{"error", err})...)
 		return
 	}
+	ctx, span := startSpan(ctx, o.tracer, spanGetPackage, o.fields()...)
+	defer func() {
+		endSpan(span, err)
+	}()
 
-	cacheKey := buildCacheKey(o.projectID, o.namespaceID, o.env, o.language)
-	now := time.Now()
+	cacheKey := o.cacheKey(o.language)
+	_, lookupSpan := startSpan(ctx, o.tracer, spanCacheLookup)
 	if val, exist := c.data.Load(cacheKey); exist {
-		realVal, ok := val.(*Package)
-		if ok && (len(o.version) == 0 || realVal.ReleaseVersion == o.version) {
-			realVal.atime = &now
-			c.data.Store(cacheKey, realVal)
-			data = realVal
-			return
+		entry, ok := val.(*cacheEntry)
+		if ok {
+			cached := entry.load()
+			if len(o.version) == 0 || cached.ReleaseVersion == o.version {
+				lookupSpan.SetAttributes(Field{"hit", true})
+				lookupSpan.End()
+				o.metricer.EmitCounter(clientCacheHitMetricsKey, 1, o.tags())
+				entry.touch(time.Now())
+				data = cached
+				return
+			}
 		}
 	}
+	lookupSpan.SetAttributes(Field{"hit", false})
+	lookupSpan.End()
+	o.metricer.EmitCounter(clientCacheMissMetricsKey, 1, o.tags())
 	var p interface{}
-	p, err = c.sf.Do(cacheKey, func() (interface{}, error) {
-		return c.getFromProxy(ctx, cacheKey, o, optArr...)
+	var shared bool
+	sfCtx, sfSpan := startSpan(ctx, o.tracer, spanSingleflight)
+	// The requests of a specific version or only version are not shared with
+	// the ones of the latest package.
+	sfKey := cacheKey + "@" + o.version
+	if o.onlyVersion {
+		sfKey += "#version"
+	}
+	p, err, shared = c.sf.do(sfKey, func() (interface{}, error) {
+		return c.getFromProxy(sfCtx, cacheKey, o, optArr...)
 	})
+	sfSpan.SetAttributes(Field{"shared", shared})
+	endSpan(sfSpan, err)
+	if shared {
+		o.metricer.EmitCounter(clientSingleflightSharedMetricsKey, 1, o.tags())
+	}
 	if err != nil {
-		o.logger.Error("starling: first fetch key %s err=%v", cacheKey, err)
+		logWith(o.logger, LevelError, "starling: first fetch failed", o.fields(Field{"error", err})...)
 		return
 	}
 	if got, ok := p.(*Package); ok {
 		data = got
-		data.projectID, data.namespaceID, data.env, data.atime = o.projectID, o.namespaceID, o.env, &now
-		if len(o.version) == 0 {
-			c.data.Store(cacheKey, data)
+		if len(o.version) == 0 && !o.onlyVersion && !shared {
+			c.data.Store(cacheKey, newCacheEntry(data, o))
+			emitHistogram(o.metricer, clientPackageSizeMetricsKey, float64(len(data.Data)), o.tags())
 		}
 	} else {
 		err = ErrBackToSourceFailed
@@ -124,19 +401,29 @@ func (c *client) getPackage(ctx context.Context, o *option, lang string, opts ..
 	return
 }
 
+// preload loads the package of the preload target into the local cache.
+func (c *client) preload(ctx context.Context, t PreloadTarget) error {
+	o := op.get()
+	defer op.put(o)
+	_, err := c.getPackage(ctx, o, t.Language, t.options()...)
+	return err
+}
+
 func (c *client) handleOptions(o *option, lang string, opts ...Option) ([]Option, error) {
 	if o == nil { // the object to handle should not be empty
 		return nil, ErrInvalidParams
 	}
-	optArr := make([]Option, len(c.options)+len(opts))
-	copy(optArr, c.options)
-	copy(optArr[len(c.options):], opts)
+	options := c.loadOptions()
+	optArr := make([]Option, len(options)+len(opts))
+	copy(optArr, options)
+	copy(optArr[len(options):], opts)
 	if len(lang) != 0 { // required lang param has highest priority
 		optArr = append(optArr, WithLanguage(lang))
 	}
 	for _, f := range optArr {
 		f(o)
 	}
+	o.logger = withRedaction(o.logger, o.appKey, o.operator)
 	if o.projectID == 0 { // use global project ID if request-level not given
 		o.projectID = c.projectID
 		optArr = append(optArr, WithProjectID(o.projectID))
@@ -156,16 +443,18 @@ func (c *client) handleOptions(o *option, lang string, opts ...Option) ([]Option
 }
 
 func (c *client) getFromProxy(ctx context.Context, key string, o *option, opts ...Option) (*Package, error) {
+	ctx, release, err := c.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	if o.onlyVersion {
-		if len(o.version) == 0 {
-			o.logger.Debug("starling: retrieve version from proxy: %v@latest", key)
-		} else {
-			o.logger.Debug("starling: retrieve version from proxy: %v@%v", key, o.version)
-		}
+		logWith(o.logger, LevelDebug, "starling: retrieve version from proxy", o.fields()...)
 		ver, rel, err := o.fetcher.FetchVersion(ctx, o.projectID, o.namespaceID, o.language, opts...)
 		if err != nil {
 			o.metricer.EmitCounter(clientRetrieveErrorMetricsKey, 1, map[string]string{"key": key})
-			o.logger.Warn("starling: fetch version from proxy failed: key=%s, err=%v", key, err)
+			logWith(o.logger, LevelWarn, "starling: fetch version from proxy failed", o.fields(Field{"error", err})...)
 			return nil, err
 		}
 		return &Package{
@@ -175,145 +464,208 @@ func (c *client) getFromProxy(ctx context.Context, key string, o *option, opts .
 		}, nil
 	}
 
-	if len(o.version) == 0 {
-		o.logger.Debug("starling: retrieve data from proxy: %v@latest", key)
-	} else {
-		o.logger.Debug("starling: retrieve data from proxy: %v@%v", key, o.version)
-	}
+	logWith(o.logger, LevelDebug, "starling: retrieve data from proxy", o.fields()...)
 	data, err := o.fetcher.Fetch(ctx, o.projectID, o.namespaceID, o.language, opts...)
 	if err != nil {
 		o.metricer.EmitCounter(clientRetrieveErrorMetricsKey, 1, map[string]string{"key": key})
-		o.logger.Warn("starling: fetch data from proxy failed: key=%s, err=%v", key, err)
+		logWith(o.logger, LevelWarn, "starling: fetch data from proxy failed", o.fields(Field{"error", err})...)
 		return nil, err
 	}
 	return data, nil
 }
 
-func (c *client) processPlural(raw, lang, defLang string, count interface{}) (text string, err error) {
-	varName, msg, icuErr := ParseICU(raw)
-	if icuErr != nil {
-		err = icuErr
-		return
-	}
+// processPlural renders the plural, selectordinal and select arguments of the
+// ICU message with the count and the arguments, the count of a plural is the
+// argument of its name if given. The counts are wrapped in the directional
+// isolates if isolated is true and the language is RTL.
+func (c *client) processPlural(raw, lang, defLang string, count interface{}, isolated bool, vars map[string]interface{}) (string, error) {
 	if len(defLang) == 0 {
 		defLang = lang
 	}
-	localize := goi18n.NewLocalizer(goi18n.NewBundle(language.Make(defLang)), lang)
-	localized, _ := localize.Localize(&goi18n.LocalizeConfig{
-		DefaultMessage: msg,
-		PluralCount:    count,
-	})
-	// Replace the `#` or `{varName}` with the plural count number.
-	countStr := fmt.Sprintf("%v", count)
-	text = strings.ReplaceAll(localized, "#", countStr)
-	if len(varName) != 0 {
-		r := regexp.MustCompile(`{\s*` + varName + `\s*}`)
-		text = r.ReplaceAllString(text, countStr)
+	nodes, err := parseMessage(raw)
+	if err != nil {
+		return "", err
 	}
-	return
+	if !hasChoice(nodes) {
+		return "", ErrInvalidICUFormat
+	}
+	var b strings.Builder
+	r := &messageRenderer{lang: lang, rules: language.Make(defLang), count: count, vars: vars,
+		isolated: isolated && LanguageDirection(lang) == DirectionRTL}
+	if err := r.render(&b, nodes, "", "#"); err != nil {
+		return "", err
+	}
+	return b.String(), nil
 }
 
-func (c *client) processVars(raw string, vars map[string]interface{}, left, right string) (string, error) {
+// processVars replaces the variables between the delimiters with the formatted
+// arguments, which support the ICU number and date formats like
+// `{n, number, percent}` and `{d, date, long}`. The arguments are wrapped in
+// the directional isolates if isolated is true and the language is RTL.
+func (c *client) processVars(raw, lang string, loc *time.Location, isolated bool, vars map[string]interface{}, left, right string) (string, error) {
 	if len(left) == 0 {
 		left = defaultLeftDelimiter
 	}
 	if len(right) == 0 {
 		right = defaultRightDelimiter
 	}
-	var b bytes.Buffer
-	var i int
-	for i < len(raw) {
-		hasLeft, hasRight := true, true
-		tmp := raw[i:]
-		pos := strings.Index(tmp, left)
-		if pos != -1 {
-			b.WriteString(raw[i : i+pos])
-			b.WriteString("{{.")
-			i += pos + len(left)
-		} else {
-			hasLeft = false
-		}
-
-		tmp = raw[i:]
-		pos = strings.Index(tmp, right)
-		if pos != -1 {
-			b.WriteString(raw[i : i+pos])
-			b.WriteString("}}")
-			i += pos + len(right)
-		} else {
-			hasRight = false
+	isolated = isolated && LanguageDirection(lang) == DirectionRTL
+	var b strings.Builder
+	for {
+		start := strings.Index(raw, left)
+		if start < 0 {
+			break
 		}
-		if !hasLeft && !hasRight {
+		end := strings.Index(raw[start+len(left):], right)
+		if end < 0 {
 			break
 		}
+		b.WriteString(raw[:start])
+		arg := raw[start+len(left) : start+len(left)+end]
+		text, err := formatArg(lang, loc, arg, vars)
+		if err != nil {
+			return "", err
+		}
+		if isolated {
+			text = isolate(text)
+		}
+		b.WriteString(text)
+		raw = raw[start+len(left)+end+len(right):]
 	}
-	b.WriteString(raw[i:])
-	t := template.Must(template.New("icu").Parse(b.String()))
-	var out bytes.Buffer
-	if err := t.Execute(&out, vars); err != nil {
-		return "", err
-	}
-	return out.String(), nil
+	b.WriteString(raw)
+	return b.String(), nil
 }
 
-func (c *client) refresher(ctx context.Context) {
+// loadOptions returns the current snapshot of the global options, which must
+// not be modified.
+func (c *client) loadOptions() []Option {
+	options, _ := c.options.Load().([]Option)
+	return options
+}
+
+// backgroundOption builds the option of the background procedures from the
+// current global options, and the invalid intervals are replaced by defaults.
+// It should be put back to the pool after using.
+func (c *client) backgroundOption() *option {
 	o := op.get()
-	defer op.put(o)
-	for _, f := range c.options {
+	for _, f := range c.loadOptions() {
 		f(o)
 	}
-	ticker := time.NewTicker(o.refreshInterval)
-	defer ticker.Stop()
-	doRefresh := func() {
-		duration := o.cacheDuration
-		data := make(map[string]interface{})
-		c.data.Range(func(key, value interface{}) bool {
-			if k, ok := key.(string); ok {
-				data[k] = value
-			}
-			return true
-		})
-		for k, v := range data {
-			now := time.Now()
-			realVal, ok := v.(*Package)
-			if !ok {
-				c.data.Delete(k)
-				continue
-			}
-			if realVal.atime.Add(duration).Before(now) {
-				c.data.Delete(k)
-				continue
-			}
-
-			o.projectID, o.namespaceID, o.env, o.language = realVal.projectID, realVal.namespaceID, realVal.env, realVal.Language
-			newVal, err := c.getFromProxy(ctx, k, o,
-				WithProjectID(realVal.projectID),
-				WithNamespaceID(realVal.namespaceID),
-				WithEnv(realVal.env),
-				WithLanguage(realVal.Language))
-			if err != nil {
-				o.logger.Info("starling: refresh key %s failed: %v", k, err)
-				continue
-			}
-
-			newVal.projectID, newVal.namespaceID, newVal.env, newVal.atime = realVal.projectID, realVal.namespaceID, realVal.env, realVal.atime
-			c.data.Store(k, newVal)
-		}
+	o.logger = withRedaction(o.logger, o.appKey, o.operator)
+	if int64(o.refreshInterval) < int64(time.Second) {
+		o.refreshInterval = defaultRefreshInterval
+	}
+	if int64(o.cacheDuration) < int64(time.Minute) {
+		o.cacheDuration = defaultCacheDuration
 	}
+	return o
+}
+
+func (c *client) refresher(ctx context.Context) {
+	defer close(c.done)
+	o := c.backgroundOption()
+	interval := o.refreshInterval
+	op.put(o)
+	ticker := time.NewTicker(interval)
+	defer func() {
+		ticker.Stop()
+	}()
 	for {
 		select {
-		case <-c.shutdownCh:
+		case <-ctx.Done():
+			o := c.backgroundOption()
 			o.logger.Info("starling: exit background refresher for client=%v:%v", c.projectID, c.namespaceID)
+			op.put(o)
 			return
 		case <-ticker.C:
-			func() {
-				defer func() {
-					if r := recover(); r != nil {
-						o.logger.Warn("starling: refresher panic for client=%v:%v, %v", c.projectID, c.namespaceID, r)
-					}
-				}()
-				doRefresh()
-			}()
+			// Reset the ticker if the refresh interval is changed by `AddOption`.
+			if d := c.refresh(ctx); d != interval {
+				ticker.Stop()
+				ticker = time.NewTicker(d)
+				interval = d
+			}
 		}
 	}
 }
+
+// refresh updates the cached packages and removes the expired ones with the
+// current global options, and returns the refresh interval of the options.
+func (c *client) refresh(ctx context.Context) (interval time.Duration) {
+	o := c.backgroundOption()
+	defer op.put(o)
+	interval = o.refreshInterval
+	defer func() {
+		if r := recover(); r != nil {
+			o.logger.Warn("starling: refresher panic for client=%v:%v, %v", c.projectID, c.namespaceID, r)
+		}
+	}()
+
+	begin := time.Now()
+	duration := o.cacheDuration
+	data := make(map[string]interface{})
+	c.data.Range(func(key, value interface{}) bool {
+		if k, ok := key.(string); ok {
+			data[k] = value
+		}
+		return true
+	})
+	options := c.loadOptions()
+	options = options[:len(options):len(options)]
+	for k, v := range data {
+		now := time.Now()
+		entry, ok := v.(*cacheEntry)
+		if !ok {
+			c.data.Delete(k)
+			continue
+		}
+		if entry.expired(now, duration) {
+			c.data.Delete(k)
+			continue
+		}
+
+		o.projectID, o.namespaceID, o.env, o.language = entry.projectID, entry.namespaceID, entry.env, entry.language
+		entryOpts := options
+		if entry.owner != nil {
+			// The current options of the handle like the app key are used
+			// instead of the ones it loaded the package with.
+			entryOpts = append(entryOpts, entry.owner.options.Load().([]Option)...)
+		}
+		entryOpts = append(entryOpts,
+			WithProjectID(entry.projectID),
+			WithNamespaceID(entry.namespaceID),
+			WithEnv(entry.env),
+			WithLanguage(entry.language))
+		if entry.owner == nil && len(entry.appKey) != 0 {
+			entryOpts = append(entryOpts, WithAppKey(entry.appKey))
+		}
+		if entry.owner == nil && len(entry.operator) != 0 {
+			entryOpts = append(entryOpts, WithOperator(entry.operator))
+		}
+		newVal, err := c.getFromProxy(ctx, k, o, entryOpts...)
+		if err != nil {
+			logWith(o.logger, LevelInfo, "starling: refresh failed", o.fields(Field{"error", err})...)
+			continue
+		}
+
+		entry.pkg.Store(newVal)
+		emitHistogram(o.metricer, clientPackageSizeMetricsKey, float64(len(newVal.Data)), o.tags())
+	}
+
+	var size int
+	c.data.Range(func(key, value interface{}) bool {
+		size++
+		return true
+	})
+	emitGauge(o.metricer, clientCacheSizeMetricsKey, float64(size), nil)
+	emitTimer(o.metricer, clientRefreshDurationMetricsKey, time.Since(begin), nil)
+
+	if c.ready.pending() {
+		c.ready.load(func(t PreloadTarget) error {
+			return c.preload(ctx, t)
+		})
+	}
+	if c.onRefresh != nil {
+		c.onRefresh(ctx)
+	}
+	return
+}
@@ -2,8 +2,12 @@ package i18n
 
 import (
 	"context"
+	"errors"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -48,9 +52,12 @@ func (m *mockFetcher) FetchVersion(ctx context.Context, pid, nid int64, lang str
 
 func TestClientNew(t *testing.T) {
 	rp := NewBackoffRetryPolicy(3, 4000, 500)
-	lg := DefaultLogger()
+	wl := WithLogger(DefaultLogger())
+	lo := &option{}
+	wl(lo)
+	lg := lo.logger
 	me := DefaultMetricer()
-	ft := NewHttpFetcher(WithLogger(lg), WithMetricer(me), WithRetryPolicy(rp))
+	ft := NewHttpFetcher(wl, WithMetricer(me), WithRetryPolicy(rp))
 
 	for _, item := range []struct {
 		pid  int64
@@ -70,7 +77,7 @@ func TestClientNew(t *testing.T) {
 		{
 			pid:  1,
 			nid:  2,
-			opts: []Option{WithAppKey("app12345"), WithRetryPolicy(rp), WithLogger(lg), WithMetricer(me), WithFetcher(ft)},
+			opts: []Option{WithAppKey("app12345"), WithRetryPolicy(rp), wl, WithMetricer(me), WithFetcher(ft)},
 
 			opt: &option{
 				appKey:          "app12345",
@@ -92,17 +99,17 @@ func TestClientNew(t *testing.T) {
 		assert.NotNil(t, c)
 
 		if item.opt == nil {
-			assert.Equal(t, 6, len(c.options))
-			c.Shutdown()
+			assert.Equal(t, 6, len(c.loadOptions()))
+			c.Shutdown(context.TODO())
 			continue
 		}
 
 		o := &option{}
-		for _, f := range c.options {
+		for _, f := range c.loadOptions() {
 			f(o)
 		}
 		assert.Equal(t, item.opt, o)
-		c.Shutdown()
+		c.Shutdown(context.TODO())
 	}
 }
 
@@ -110,10 +117,10 @@ func TestClientGetPackage(t *testing.T) {
 	c, err := NewClient(1, 2, WithFetcher(&mockFetcher{}))
 	assert.NotNil(t, c)
 	assert.Nil(t, err)
-	defer c.Shutdown()
+	defer c.Shutdown(context.TODO())
 
 	pkg, err := c.GetPackage(context.TODO(), "en", WithProjectID(-1))
-	assert.Equal(t, ErrInvalidParams, err)
+	assert.True(t, errors.Is(err, ErrInvalidParams))
 	assert.Nil(t, nil, pkg)
 
 	// Test get from fetcher.
@@ -134,7 +141,7 @@ func TestClientGetPackage(t *testing.T) {
 
 	// Test fetch failed.
 	pkg, err = c.GetPackage(context.TODO(), "INVALID")
-	assert.Equal(t, ErrBackToSourceFailed, err)
+	assert.True(t, errors.Is(err, ErrBackToSourceFailed))
 	assert.Nil(t, pkg)
 
 	// Test fetch version only.
@@ -144,7 +151,7 @@ func TestClientGetPackage(t *testing.T) {
 
 	// Test fetch version only failed.
 	pkg, err = c.GetPackage(context.TODO(), "INVALID", WithOnlyVersion(true))
-	assert.Equal(t, ErrBackToSourceFailed, err)
+	assert.True(t, errors.Is(err, ErrBackToSourceFailed))
 	assert.Nil(t, pkg)
 }
 
@@ -152,16 +159,16 @@ func TestClientGetText(t *testing.T) {
 	c, err := NewClient(1, 2, WithFetcher(&mockFetcher{}))
 	assert.NotNil(t, c)
 	assert.Nil(t, err)
-	defer c.Shutdown()
+	defer c.Shutdown(context.TODO())
 
 	// Test get data failed.
 	text, err := c.GetText(context.TODO(), "INVALID", "k1")
-	assert.Equal(t, ErrBackToSourceFailed, err)
+	assert.True(t, errors.Is(err, ErrBackToSourceFailed))
 	assert.Empty(t, text)
 
 	// Test key not exist.
 	text, err = c.GetText(context.TODO(), "en", "not-exist-key")
-	assert.Equal(t, ErrKeyNotExist, err)
+	assert.True(t, errors.Is(err, ErrKeyNotExist))
 	assert.Empty(t, text)
 
 	// Test plain text.
@@ -208,7 +215,7 @@ func TestClientHandleOption(t *testing.T) {
 	c, err := NewClient(1, 2)
 	assert.NotNil(t, c)
 	assert.Nil(t, err)
-	defer c.Shutdown()
+	defer c.Shutdown(context.TODO())
 
 	for _, item := range []struct {
 		opt  *option
@@ -243,7 +250,7 @@ func TestClientHandleOption(t *testing.T) {
 		for _, f := range optArr {
 			f(actual)
 		}
-		for _, f := range c.options {
+		for _, f := range c.loadOptions() {
 			f(item.expected)
 		}
 		assert.Equal(t, item.expected, actual)
@@ -255,7 +262,7 @@ func TestClientProcessPlural(t *testing.T) {
 	c, err := NewClient(1, 2, WithFetcher(&mockFetcher{}))
 	assert.NotNil(t, c)
 	assert.Nil(t, err)
-	defer c.Shutdown()
+	defer c.Shutdown(context.TODO())
 
 	for _, item := range []struct {
 		raw     string
@@ -288,7 +295,7 @@ func TestClientProcessPlural(t *testing.T) {
 			result:  "I have 1 apple to store.",
 		},
 	} {
-		res, err := c.processPlural(item.raw, item.lang, item.defLang, item.count)
+		res, err := c.processPlural(item.raw, item.lang, item.defLang, item.count, false, nil)
 		t.Log(res, err)
 		assert.Equal(t, item.err, err)
 		assert.Equal(t, item.result, res)
@@ -299,7 +306,7 @@ func TestClientProcessVars(t *testing.T) {
 	c, err := NewClient(1, 2, WithFetcher(&mockFetcher{}))
 	assert.NotNil(t, c)
 	assert.Nil(t, err)
-	defer c.Shutdown()
+	defer c.Shutdown(context.TODO())
 
 	for _, item := range []struct {
 		raw   string
@@ -340,9 +347,114 @@ func TestClientProcessVars(t *testing.T) {
 			result: "I have [[count]] apples with [[attitude]]",
 		},
 	} {
-		res, err := c.processVars(item.raw, item.vars, item.left, item.right)
+		res, err := c.processVars(item.raw, "en", nil, false, item.vars, item.left, item.right)
 		t.Log(res, err)
 		assert.Equal(t, item.err, err)
 		assert.Equal(t, item.result, res)
 	}
 }
+
+type blockFetcher struct {
+	mockFetcher
+	started chan struct{}
+}
+
+func (b *blockFetcher) Fetch(ctx context.Context, pid, nid int64, lang string, opts ...Option) (*Package, error) {
+	if lang != "block" {
+		return b.mockFetcher.Fetch(ctx, pid, nid, lang, opts...)
+	}
+	close(b.started)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+type flushMetricer struct {
+	Metricer
+	flushed int32
+}
+
+func (f *flushMetricer) Flush(ctx context.Context) error {
+	atomic.AddInt32(&f.flushed, 1)
+	return nil
+}
+
+func TestClientShutdown(t *testing.T) {
+	var hooked int32
+	fetcher := &blockFetcher{started: make(chan struct{})}
+	metricer := &flushMetricer{Metricer: DefaultMetricer()}
+	c, err := NewClient(1, 2, WithFetcher(fetcher), WithMetricer(metricer),
+		WithShutdownHooks(func(ctx context.Context) error {
+			atomic.AddInt32(&hooked, 1)
+			return nil
+		}))
+	assert.NotNil(t, c)
+	assert.Nil(t, err)
+
+	_, err = c.GetPackage(context.TODO(), "en")
+	assert.Nil(t, err)
+
+	// Test the in-flight fetching is canceled.
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := c.GetPackage(context.TODO(), "block")
+		errCh <- err
+	}()
+	<-fetcher.started
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, c.Shutdown(context.TODO()))
+		}()
+	}
+	wg.Wait()
+	assert.True(t, errors.Is(<-errCh, context.Canceled))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&hooked))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&metricer.flushed))
+
+	// Test the cached package is still served after shutdown.
+	pkg, err := c.GetPackage(context.TODO(), "en")
+	assert.Nil(t, err)
+	assert.Equal(t, "en", pkg.Language)
+	_, err = c.GetPackage(context.TODO(), "de")
+	assert.True(t, errors.Is(err, ErrClientClosed))
+	assert.Nil(t, c.Shutdown(context.TODO()))
+}
+
+func TestClientAddOption(t *testing.T) {
+	c, err := NewClient(1, 2, WithFetcher(&mockFetcher{}))
+	assert.NotNil(t, c)
+	assert.Nil(t, err)
+	defer c.Shutdown(context.TODO())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			c.AddOption(WithEnv(EnvTest), WithLogger(DefaultLogger()))
+		}(i)
+		go func() {
+			defer wg.Done()
+			_, err := c.GetText(context.TODO(), "en", "key1")
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+	// The added options are merged instead of growing.
+	assert.Equal(t, 1, len(c.loadOptions()))
+	peekOptions(c.loadOptions(), nil, func(o *option) {
+		assert.Equal(t, EnvTest, o.env)
+		assert.NotNil(t, o.fetcher)
+	})
+
+	// Test the refresher picks up the new options.
+	assert.Equal(t, defaultRefreshInterval, c.refresh(context.TODO()))
+	c.AddOption(WithRefreshInterval(2*time.Second), WithCacheDuration(time.Millisecond))
+	assert.Equal(t, 2*time.Second, c.refresh(context.TODO()))
+	o := c.backgroundOption()
+	assert.Equal(t, defaultCacheDuration, o.cacheDuration)
+	op.put(o)
+}
@@ -0,0 +1,131 @@
+// Command starling-lint checks the messages of the i18n packages, and prints a
+// report of the issues found. It exits with 1 if there is any error.
+//
+// The packages are read from the JSON files of `i18n.Package`, or fetched from
+// the server if the project is given:
+//
+//	starling-lint -source en en.json ja.json
+//	starling-lint -source en -project 1 -namespace 2 -appkey AppKey -langs en,ja,de
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	i18n "github.com/volcengine/i18n-sdk-golang"
+)
+
+func main() {
+	var (
+		source    = flag.String("source", "en", "the source language to check the placeholders against")
+		left      = flag.String("left", "", "the left delimiter of the custom variables")
+		right     = flag.String("right", "", "the right delimiter of the custom variables")
+		format    = flag.String("format", "json", "the report format, json or text")
+		project   = flag.Int64("project", 0, "the project ID to fetch the packages")
+		namespace = flag.Int64("namespace", 0, "the namespace ID to fetch the packages")
+		appKey    = flag.String("appkey", "", "the app key to fetch the packages")
+		operator  = flag.String("operator", "", "the operator to fetch the packages")
+		env       = flag.String("env", i18n.EnvNormal, "the env to fetch the packages")
+		langs     = flag.String("langs", "", "the comma separated languages to fetch, required with -project")
+	)
+	flag.Parse()
+
+	var pkgs []*i18n.Package
+	var err error
+	if *project != 0 {
+		pkgs, err = fetch(*project, *namespace, splitLangs(*langs),
+			i18n.WithAppKey(*appKey), i18n.WithOperator(*operator), i18n.WithEnv(*env))
+	} else {
+		pkgs, err = read(flag.Args())
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "starling-lint:", err)
+		os.Exit(2)
+	}
+
+	var opts []i18n.Option
+	if len(*left) != 0 && len(*right) != 0 {
+		opts = append(opts, i18n.WithLeftDelimiter(*left), i18n.WithRightDelimiter(*right))
+	}
+	report := i18n.Lint(*source, pkgs, opts...)
+	if *format == "text" {
+		for _, issue := range report.Issues {
+			fmt.Println(issue)
+		}
+		fmt.Printf("%d packages, %d messages, %d errors, %d warnings\n",
+			report.Packages, report.Messages, report.Errors, report.Warnings)
+	} else {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(report)
+	}
+	if report.Errors != 0 {
+		os.Exit(1)
+	}
+}
+
+func read(files []string) ([]*i18n.Package, error) {
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no package files given")
+	}
+	pkgs := make([]*i18n.Package, 0, len(files))
+	for _, name := range files {
+		data, err := ioutil.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		pkg := &i18n.Package{}
+		if err := json.Unmarshal(data, pkg); err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs, nil
+}
+
+// splitLangs splits the comma separated languages without the empty ones.
+func splitLangs(s string) []string {
+	var langs []string
+	for _, lang := range strings.Split(s, ",") {
+		if lang = strings.TrimSpace(lang); len(lang) != 0 {
+			langs = append(langs, lang)
+		}
+	}
+	return langs
+}
+
+// nopMetricer drops the metrics, which are not wanted in the report.
+type nopMetricer struct{}
+
+func (nopMetricer) EmitCounter(string, interface{}, map[string]string) {}
+
+func fetch(pid, nid int64, langs []string, opts ...i18n.Option) ([]*i18n.Package, error) {
+	if len(langs) == 0 {
+		return nil, fmt.Errorf("no languages given by -langs")
+	}
+	c, err := i18n.NewClient(pid, nid, append(opts,
+		i18n.WithLogger(i18n.DefaultLoggerWithLevel(i18n.LevelOff)),
+		i18n.WithMetricer(nopMetricer{}))...)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	defer c.Shutdown(ctx)
+
+	got, err := c.GetPackages(ctx, langs, i18n.WithDisableBackupLang(true))
+	if err != nil {
+		return nil, err
+	}
+	pkgs := make([]*i18n.Package, 0, len(langs))
+	for _, lang := range langs {
+		pkgs = append(pkgs, got[lang])
+	}
+	return pkgs, nil
+}
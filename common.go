@@ -26,6 +26,15 @@ const (
 	clientPackageEmptyMetricsKey  = "client.package.empty"
 	clientKeyEmptyMetricsKey      = "client.key.empty"
 
+	clientCacheHitMetricsKey           = "client.cache.hit"
+	clientCacheMissMetricsKey          = "client.cache.miss"
+	clientCacheSizeMetricsKey          = "client.cache.size"
+	clientPackageSizeMetricsKey        = "client.package.size"
+	clientRefreshDurationMetricsKey    = "client.refresh.duration"
+	clientSingleflightSharedMetricsKey = "client.singleflight.shared"
+	clientLayerHitMetricsKey           = "client.layer.hit"
+	clientOverrideHitMetricsKey        = "client.override.hit"
+
 	defaultLeftDelimiter   = "{"
 	defaultRightDelimiter  = "}"
 	defaultRefreshInterval = time.Minute
@@ -46,6 +55,12 @@ var (
 	ErrKeyNotExist        = errors.New("given key not exist")
 	ErrBackToSourceFailed = errors.New("back to source to fetch data failed")
 	ErrInvalidICUFormat   = errors.New("invalid ICU format string")
+	ErrNotReady           = errors.New("preload packages not loaded yet")
+	ErrClientClosed       = errors.New("client is shutdown")
+	ErrAlreadyRegistered  = errors.New("project and namespace already registered")
+	ErrMissingArgument    = errors.New("missing message argument")
+	ErrInvalidRichText    = errors.New("invalid rich text")
+	ErrNoAppKey           = errors.New("no app-key given")
 )
 
 var (
@@ -0,0 +1,286 @@
+package i18n
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// dateLocale is the CLDR calendar data of a language used to format the
+// dates, times and relative times.
+type dateLocale struct {
+	months, monthsAbbr     [12]string
+	standalone             [12]string // the stand-alone wide months if differ
+	weekdays, weekdaysAbbr [7]string
+	dayPeriods             [2]string
+	dates, times           map[string]string // style -> pattern
+	dateTime               string            // {1} is the date and {0} is the time
+	skeletons              map[string]string // skeleton -> pattern
+	relative               map[string]relativeUnit
+	now                    string
+}
+
+// relativeUnit holds the patterns of the past and future relative times of a
+// unit, keyed by the plural form.
+type relativeUnit struct {
+	past, future map[string]string
+}
+
+// relativeUnits are the units of the relative time, from the largest.
+var relativeUnits = []struct {
+	name string
+	d    time.Duration
+}{
+	{"year", 365 * 24 * time.Hour},
+	{"month", 30 * 24 * time.Hour},
+	{"week", 7 * 24 * time.Hour},
+	{"day", 24 * time.Hour},
+	{"hour", time.Hour},
+	{"minute", time.Minute},
+	{"second", time.Second},
+}
+
+// formatTime formats the time value of the argument with the type and style:
+//
+//   - `date` and `time` with the style `short`, `medium` (default), `long`,
+//     `full`, a skeleton like `::yMMMd` or a CLDR pattern like `dd.MM.y`.
+//   - `relative` with an optional unit like `day`, where a `time.Duration` is
+//     an offset from now, negative in the past.
+//   - no type, a `time.Time` is formatted as a short date and time, and a
+//     `time.Duration` as a relative time.
+//
+// The value which is not a time is formatted with `%v`.
+func formatTime(lang string, loc *time.Location, val interface{}, typ, style string) (string, error) {
+	var t time.Time
+	var offset time.Duration
+	switch v := val.(type) {
+	case time.Time:
+		t, offset = v, time.Until(v)
+		if loc != nil {
+			t = t.In(loc)
+		}
+	case time.Duration:
+		if typ != "relative" && len(typ) != 0 {
+			return fmt.Sprintf("%v", val), nil
+		}
+		return formatRelative(lang, v, style)
+	default:
+		return fmt.Sprintf("%v", val), nil
+	}
+
+	l := dateLocaleOf(lang)
+	switch typ {
+	case "":
+		date := formatDate(l, t, l.dates["short"])
+		return strings.NewReplacer("{1}", date, "{0}", formatDate(l, t, l.times["short"])).Replace(l.dateTime), nil
+	case "relative":
+		return formatRelative(lang, offset, style)
+	case "date", "time":
+		styles := l.dates
+		if typ == "time" {
+			styles = l.times
+		}
+		if len(style) == 0 {
+			style = "medium"
+		}
+		if pattern, ok := styles[style]; ok {
+			return formatDate(l, t, pattern), nil
+		}
+		if strings.HasPrefix(style, "::") {
+			pattern, ok := l.skeletons[style[2:]]
+			if !ok {
+				return "", fmt.Errorf("%w: unknown skeleton %q", ErrInvalidICUFormat, style)
+			}
+			return formatDate(l, t, pattern), nil
+		}
+		return formatDate(l, t, style), nil
+	}
+	return fmt.Sprintf("%v", val), nil
+}
+
+// formatRelative formats the offset from now as a relative time like
+// "3 days ago" or "in 2 hours", in the given unit or the largest one.
+func formatRelative(lang string, d time.Duration, unit string) (string, error) {
+	l := dateLocaleOf(lang)
+	abs := d
+	if abs < 0 {
+		abs = -abs
+	}
+	if len(unit) == 0 {
+		if abs < time.Second {
+			return l.now, nil
+		}
+		for _, u := range relativeUnits {
+			if abs >= u.d {
+				unit = u.name
+				break
+			}
+		}
+	}
+	var n int
+	for _, u := range relativeUnits {
+		if u.name == unit {
+			n = int(abs / u.d)
+		}
+	}
+	patterns, ok := l.relative[unit]
+	if !ok {
+		return "", fmt.Errorf("%w: unknown relative time unit %q", ErrInvalidICUFormat, unit)
+	}
+	forms := patterns.future
+	if d < 0 {
+		forms = patterns.past
+	}
+	pattern, ok := forms[pluralForms[plural.Cardinal.MatchPlural(language.Make(lang), n, 0, 0, 0, 0)]]
+	if !ok {
+		pattern = forms["other"]
+	}
+	num, _ := formatNumber(lang, n, "")
+	return strings.Replace(pattern, "{0}", num, 1), nil
+}
+
+// formatDate formats the time with the CLDR date pattern, where the letters
+// are the fields and the text in apostrophes is literal.
+func formatDate(l *dateLocale, t time.Time, pattern string) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		switch {
+		case c == '\'':
+			if i+1 < len(pattern) && pattern[i+1] == '\'' {
+				b.WriteByte('\'')
+				i += 2
+				continue
+			}
+			for i++; i < len(pattern); i++ {
+				if pattern[i] == '\'' {
+					if i+1 < len(pattern) && pattern[i+1] == '\'' {
+						b.WriteByte('\'')
+						i++
+						continue
+					}
+					i++
+					break
+				}
+				b.WriteByte(pattern[i])
+			}
+		case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+			j := i
+			for j < len(pattern) && pattern[j] == c {
+				j++
+			}
+			b.WriteString(formatField(l, t, c, j-i))
+			i = j
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+func formatField(l *dateLocale, t time.Time, c byte, n int) string {
+	pad := func(v int) string {
+		s := strconv.Itoa(v)
+		for len(s) < n {
+			s = "0" + s
+		}
+		return s
+	}
+	switch c {
+	case 'G':
+		return "AD"
+	case 'y':
+		if n == 2 {
+			return fmt.Sprintf("%02d", t.Year()%100)
+		}
+		return pad(t.Year())
+	case 'M', 'L':
+		switch {
+		case n == 3:
+			return l.monthsAbbr[t.Month()-1]
+		case n >= 4 && c == 'L' && len(l.standalone[0]) != 0:
+			return l.standalone[t.Month()-1]
+		case n >= 4:
+			return l.months[t.Month()-1]
+		}
+		return pad(int(t.Month()))
+	case 'd':
+		return pad(t.Day())
+	case 'E', 'c':
+		if n >= 4 {
+			return l.weekdays[t.Weekday()]
+		}
+		return l.weekdaysAbbr[t.Weekday()]
+	case 'a':
+		return l.dayPeriods[t.Hour()/12]
+	case 'h':
+		h := t.Hour() % 12
+		if h == 0 {
+			h = 12
+		}
+		return pad(h)
+	case 'H':
+		return pad(t.Hour())
+	case 'K':
+		return pad(t.Hour() % 12)
+	case 'k':
+		h := t.Hour()
+		if h == 0 {
+			h = 24
+		}
+		return pad(h)
+	case 'm':
+		return pad(t.Minute())
+	case 's':
+		return pad(t.Second())
+	case 'S':
+		return fmt.Sprintf("%09d", t.Nanosecond())[:min(n, 9)]
+	case 'z', 'v', 'V':
+		if n >= 4 {
+			return t.Location().String()
+		}
+		return t.Format("MST")
+	case 'Z', 'x', 'X':
+		if n >= 4 {
+			return t.Format("-07:00")
+		}
+		return t.Format("-0700")
+	}
+	return strings.Repeat(string(c), n)
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// dateLocaleOf returns the calendar data of the language, or English if the
+// language is not supported.
+func dateLocaleOf(lang string) *dateLocale {
+	base, _ := language.Make(lang).Base()
+	if l, ok := dateLocales[base.String()]; ok {
+		return l
+	}
+	return dateLocales["en"]
+}
+
+// regularRelative builds the relative patterns of the languages where the past
+// and future patterns of each unit only differ in the prefix or suffix.
+func regularRelative(past, future string, units map[string][2]string) map[string]relativeUnit {
+	rel := make(map[string]relativeUnit, len(units))
+	for unit, names := range units {
+		one, other := names[0], names[1]
+		rel[unit] = relativeUnit{
+			past:   map[string]string{"one": strings.Replace(past, "%s", one, 1), "other": strings.Replace(past, "%s", other, 1)},
+			future: map[string]string{"one": strings.Replace(future, "%s", one, 1), "other": strings.Replace(future, "%s", other, 1)},
+		}
+	}
+	return rel
+}
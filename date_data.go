@@ -0,0 +1,213 @@
+package i18n
+
+import "strings"
+
+// dateLocales is the calendar data of the supported languages, taken from
+// CLDR. The other languages are formatted in English.
+var dateLocales = map[string]*dateLocale{
+	"en": {
+		months:       [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		monthsAbbr:   [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		weekdays:     [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		weekdaysAbbr: [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		dayPeriods:   [2]string{"AM", "PM"},
+		dates:        map[string]string{"full": "EEEE, MMMM d, y", "long": "MMMM d, y", "medium": "MMM d, y", "short": "M/d/yy"},
+		times:        map[string]string{"full": "h:mm:ss a zzzz", "long": "h:mm:ss a z", "medium": "h:mm:ss a", "short": "h:mm a"},
+		dateTime:     "{1}, {0}",
+		skeletons: map[string]string{
+			"yMd": "M/d/y", "yMMMd": "MMM d, y", "yMMMMd": "MMMM d, y", "yMMMEd": "EEE, MMM d, y", "yMMM": "MMM y", "yMMMM": "MMMM y",
+			"Md": "M/d", "MMMd": "MMM d", "MMMMd": "MMMM d", "MMMEd": "EEE, MMM d",
+			"Hm": "HH:mm", "Hms": "HH:mm:ss", "hm": "h:mm a", "hms": "h:mm:ss a",
+		},
+		relative: regularRelative("{0} %s ago", "in {0} %s", map[string][2]string{
+			"second": {"second", "seconds"}, "minute": {"minute", "minutes"}, "hour": {"hour", "hours"},
+			"day": {"day", "days"}, "week": {"week", "weeks"}, "month": {"month", "months"}, "year": {"year", "years"},
+		}),
+		now: "now",
+	},
+	"zh": {
+		months:       [12]string{"一月", "二月", "三月", "四月", "五月", "六月", "七月", "八月", "九月", "十月", "十一月", "十二月"},
+		monthsAbbr:   [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		weekdays:     [7]string{"星期日", "星期一", "星期二", "星期三", "星期四", "星期五", "星期六"},
+		weekdaysAbbr: [7]string{"周日", "周一", "周二", "周三", "周四", "周五", "周六"},
+		dayPeriods:   [2]string{"上午", "下午"},
+		dates:        map[string]string{"full": "y年M月d日EEEE", "long": "y年M月d日", "medium": "y年M月d日", "short": "y/M/d"},
+		times:        map[string]string{"full": "zzzz HH:mm:ss", "long": "z HH:mm:ss", "medium": "HH:mm:ss", "short": "HH:mm"},
+		dateTime:     "{1} {0}",
+		skeletons: map[string]string{
+			"yMd": "y/M/d", "yMMMd": "y年M月d日", "yMMMMd": "y年M月d日", "yMMMEd": "y年M月d日EEE", "yMMM": "y年M月", "yMMMM": "y年M月",
+			"Md": "M/d", "MMMd": "M月d日", "MMMMd": "M月d日", "MMMEd": "M月d日EEE",
+			"Hm": "HH:mm", "Hms": "HH:mm:ss", "hm": "ah:mm", "hms": "ah:mm:ss",
+		},
+		relative: regularRelative("{0}%s前", "{0}%s后", map[string][2]string{
+			"second": {"秒钟", "秒钟"}, "minute": {"分钟", "分钟"}, "hour": {"小时", "小时"},
+			"day": {"天", "天"}, "week": {"周", "周"}, "month": {"个月", "个月"}, "year": {"年", "年"},
+		}),
+		now: "现在",
+	},
+	"ja": {
+		months:       [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		monthsAbbr:   [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		weekdays:     [7]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"},
+		weekdaysAbbr: [7]string{"日", "月", "火", "水", "木", "金", "土"},
+		dayPeriods:   [2]string{"午前", "午後"},
+		dates:        map[string]string{"full": "y年M月d日EEEE", "long": "y年M月d日", "medium": "y/MM/dd", "short": "y/MM/dd"},
+		times:        map[string]string{"full": "H時mm分ss秒 zzzz", "long": "H:mm:ss z", "medium": "H:mm:ss", "short": "H:mm"},
+		dateTime:     "{1} {0}",
+		skeletons: map[string]string{
+			"yMd": "y/M/d", "yMMMd": "y年M月d日", "yMMMMd": "y年M月d日", "yMMMEd": "y年M月d日(EEE)", "yMMM": "y年M月", "yMMMM": "y年M月",
+			"Md": "M/d", "MMMd": "M月d日", "MMMMd": "M月d日", "MMMEd": "M月d日(EEE)",
+			"Hm": "H:mm", "Hms": "H:mm:ss", "hm": "ah:mm", "hms": "ah:mm:ss",
+		},
+		relative: regularRelative("{0} %s前", "{0} %s後", map[string][2]string{
+			"second": {"秒", "秒"}, "minute": {"分", "分"}, "hour": {"時間", "時間"},
+			"day": {"日", "日"}, "week": {"週間", "週間"}, "month": {"か月", "か月"}, "year": {"年", "年"},
+		}),
+		now: "今",
+	},
+	"ko": {
+		months:       [12]string{"1월", "2월", "3월", "4월", "5월", "6월", "7월", "8월", "9월", "10월", "11월", "12월"},
+		monthsAbbr:   [12]string{"1월", "2월", "3월", "4월", "5월", "6월", "7월", "8월", "9월", "10월", "11월", "12월"},
+		weekdays:     [7]string{"일요일", "월요일", "화요일", "수요일", "목요일", "금요일", "토요일"},
+		weekdaysAbbr: [7]string{"일", "월", "화", "수", "목", "금", "토"},
+		dayPeriods:   [2]string{"오전", "오후"},
+		dates:        map[string]string{"full": "y년 MMMM d일 EEEE", "long": "y년 MMMM d일", "medium": "y. M. d.", "short": "yy. M. d."},
+		times:        map[string]string{"full": "a h시 m분 s초 zzzz", "long": "a h시 m분 s초 z", "medium": "a h:mm:ss", "short": "a h:mm"},
+		dateTime:     "{1} {0}",
+		skeletons: map[string]string{
+			"yMd": "y. M. d.", "yMMMd": "y년 MMM d일", "yMMMMd": "y년 MMMM d일", "yMMMEd": "y년 MMM d일 (E)", "yMMM": "y년 MMM", "yMMMM": "y년 MMMM",
+			"Md": "M. d.", "MMMd": "MMM d일", "MMMMd": "MMMM d일", "MMMEd": "MMM d일 (E)",
+			"Hm": "HH:mm", "Hms": "H시 m분 s초", "hm": "a h:mm", "hms": "a h:mm:ss",
+		},
+		relative: regularRelative("{0}%s 전", "{0}%s 후", map[string][2]string{
+			"second": {"초", "초"}, "minute": {"분", "분"}, "hour": {"시간", "시간"},
+			"day": {"일", "일"}, "week": {"주", "주"}, "month": {"개월", "개월"}, "year": {"년", "년"},
+		}),
+		now: "지금",
+	},
+	"de": {
+		months:       [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		monthsAbbr:   [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		weekdays:     [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		weekdaysAbbr: [7]string{"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+		dayPeriods:   [2]string{"AM", "PM"},
+		dates:        map[string]string{"full": "EEEE, d. MMMM y", "long": "d. MMMM y", "medium": "dd.MM.y", "short": "dd.MM.yy"},
+		times:        map[string]string{"full": "HH:mm:ss zzzz", "long": "HH:mm:ss z", "medium": "HH:mm:ss", "short": "HH:mm"},
+		dateTime:     "{1}, {0}",
+		skeletons: map[string]string{
+			"yMd": "d.M.y", "yMMMd": "d. MMM y", "yMMMMd": "d. MMMM y", "yMMMEd": "EEE, d. MMM y", "yMMM": "MMM y", "yMMMM": "MMMM y",
+			"Md": "d.M.", "MMMd": "d. MMM", "MMMMd": "d. MMMM", "MMMEd": "EEE, d. MMM",
+			"Hm": "HH:mm", "Hms": "HH:mm:ss", "hm": "h:mm a", "hms": "h:mm:ss a",
+		},
+		relative: map[string]relativeUnit{
+			"second": {past: map[string]string{"one": "vor {0} Sekunde", "other": "vor {0} Sekunden"}, future: map[string]string{"one": "in {0} Sekunde", "other": "in {0} Sekunden"}},
+			"minute": {past: map[string]string{"one": "vor {0} Minute", "other": "vor {0} Minuten"}, future: map[string]string{"one": "in {0} Minute", "other": "in {0} Minuten"}},
+			"hour":   {past: map[string]string{"one": "vor {0} Stunde", "other": "vor {0} Stunden"}, future: map[string]string{"one": "in {0} Stunde", "other": "in {0} Stunden"}},
+			"day":    {past: map[string]string{"one": "vor {0} Tag", "other": "vor {0} Tagen"}, future: map[string]string{"one": "in {0} Tag", "other": "in {0} Tagen"}},
+			"week":   {past: map[string]string{"one": "vor {0} Woche", "other": "vor {0} Wochen"}, future: map[string]string{"one": "in {0} Woche", "other": "in {0} Wochen"}},
+			"month":  {past: map[string]string{"one": "vor {0} Monat", "other": "vor {0} Monaten"}, future: map[string]string{"one": "in {0} Monat", "other": "in {0} Monaten"}},
+			"year":   {past: map[string]string{"one": "vor {0} Jahr", "other": "vor {0} Jahren"}, future: map[string]string{"one": "in {0} Jahr", "other": "in {0} Jahren"}},
+		},
+		now: "jetzt",
+	},
+	"fr": {
+		months:       [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		monthsAbbr:   [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		weekdays:     [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		weekdaysAbbr: [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+		dayPeriods:   [2]string{"AM", "PM"},
+		dates:        map[string]string{"full": "EEEE d MMMM y", "long": "d MMMM y", "medium": "d MMM y", "short": "dd/MM/y"},
+		times:        map[string]string{"full": "HH:mm:ss zzzz", "long": "HH:mm:ss z", "medium": "HH:mm:ss", "short": "HH:mm"},
+		dateTime:     "{1} {0}",
+		skeletons: map[string]string{
+			"yMd": "dd/MM/y", "yMMMd": "d MMM y", "yMMMMd": "d MMMM y", "yMMMEd": "EEE d MMM y", "yMMM": "MMM y", "yMMMM": "MMMM y",
+			"Md": "dd/MM", "MMMd": "d MMM", "MMMMd": "d MMMM", "MMMEd": "EEE d MMM",
+			"Hm": "HH:mm", "Hms": "HH:mm:ss", "hm": "h:mm a", "hms": "h:mm:ss a",
+		},
+		relative: regularRelative("il y a {0} %s", "dans {0} %s", map[string][2]string{
+			"second": {"seconde", "secondes"}, "minute": {"minute", "minutes"}, "hour": {"heure", "heures"},
+			"day": {"jour", "jours"}, "week": {"semaine", "semaines"}, "month": {"mois", "mois"}, "year": {"an", "ans"},
+		}),
+		now: "maintenant",
+	},
+	"es": {
+		months:       [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		monthsAbbr:   [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		weekdays:     [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		weekdaysAbbr: [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		dayPeriods:   [2]string{"a. m.", "p. m."},
+		dates:        map[string]string{"full": "EEEE, d 'de' MMMM 'de' y", "long": "d 'de' MMMM 'de' y", "medium": "d MMM y", "short": "d/M/yy"},
+		times:        map[string]string{"full": "H:mm:ss (zzzz)", "long": "H:mm:ss z", "medium": "H:mm:ss", "short": "H:mm"},
+		dateTime:     "{1}, {0}",
+		skeletons: map[string]string{
+			"yMd": "d/M/y", "yMMMd": "d MMM y", "yMMMMd": "d 'de' MMMM 'de' y", "yMMMEd": "EEE, d MMM y", "yMMM": "MMM y", "yMMMM": "MMMM 'de' y",
+			"Md": "d/M", "MMMd": "d MMM", "MMMMd": "d 'de' MMMM", "MMMEd": "EEE, d MMM",
+			"Hm": "H:mm", "Hms": "H:mm:ss", "hm": "h:mm a", "hms": "h:mm:ss a",
+		},
+		relative: regularRelative("hace {0} %s", "dentro de {0} %s", map[string][2]string{
+			"second": {"segundo", "segundos"}, "minute": {"minuto", "minutos"}, "hour": {"hora", "horas"},
+			"day": {"día", "días"}, "week": {"semana", "semanas"}, "month": {"mes", "meses"}, "year": {"año", "años"},
+		}),
+		now: "ahora",
+	},
+	"pt": {
+		months:       [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		monthsAbbr:   [12]string{"jan.", "fev.", "mar.", "abr.", "mai.", "jun.", "jul.", "ago.", "set.", "out.", "nov.", "dez."},
+		weekdays:     [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		weekdaysAbbr: [7]string{"dom.", "seg.", "ter.", "qua.", "qui.", "sex.", "sáb."},
+		dayPeriods:   [2]string{"AM", "PM"},
+		dates:        map[string]string{"full": "EEEE, d 'de' MMMM 'de' y", "long": "d 'de' MMMM 'de' y", "medium": "d 'de' MMM 'de' y", "short": "dd/MM/y"},
+		times:        map[string]string{"full": "HH:mm:ss zzzz", "long": "HH:mm:ss z", "medium": "HH:mm:ss", "short": "HH:mm"},
+		dateTime:     "{1} {0}",
+		skeletons: map[string]string{
+			"yMd": "dd/MM/y", "yMMMd": "d 'de' MMM 'de' y", "yMMMMd": "d 'de' MMMM 'de' y", "yMMMEd": "EEE, d 'de' MMM 'de' y", "yMMM": "MMM 'de' y", "yMMMM": "MMMM 'de' y",
+			"Md": "d/M", "MMMd": "d 'de' MMM", "MMMMd": "d 'de' MMMM", "MMMEd": "EEE, d 'de' MMM",
+			"Hm": "HH:mm", "Hms": "HH:mm:ss", "hm": "h:mm a", "hms": "h:mm:ss a",
+		},
+		relative: regularRelative("há {0} %s", "em {0} %s", map[string][2]string{
+			"second": {"segundo", "segundos"}, "minute": {"minuto", "minutos"}, "hour": {"hora", "horas"},
+			"day": {"dia", "dias"}, "week": {"semana", "semanas"}, "month": {"mês", "meses"}, "year": {"ano", "anos"},
+		}),
+		now: "agora",
+	},
+	"ru": {
+		months:       [12]string{"января", "февраля", "марта", "апреля", "мая", "июня", "июля", "августа", "сентября", "октября", "ноября", "декабря"},
+		monthsAbbr:   [12]string{"янв.", "февр.", "мар.", "апр.", "мая", "июн.", "июл.", "авг.", "сент.", "окт.", "нояб.", "дек."},
+		standalone:   [12]string{"январь", "февраль", "март", "апрель", "май", "июнь", "июль", "август", "сентябрь", "октябрь", "ноябрь", "декабрь"},
+		weekdays:     [7]string{"воскресенье", "понедельник", "вторник", "среда", "четверг", "пятница", "суббота"},
+		weekdaysAbbr: [7]string{"вс", "пн", "вт", "ср", "чт", "пт", "сб"},
+		dayPeriods:   [2]string{"AM", "PM"},
+		dates:        map[string]string{"full": "EEEE, d MMMM y 'г'.", "long": "d MMMM y 'г'.", "medium": "d MMM y 'г'.", "short": "dd.MM.y"},
+		times:        map[string]string{"full": "HH:mm:ss zzzz", "long": "HH:mm:ss z", "medium": "HH:mm:ss", "short": "HH:mm"},
+		dateTime:     "{1}, {0}",
+		skeletons: map[string]string{
+			"yMd": "dd.MM.y", "yMMMd": "d MMM y 'г'.", "yMMMMd": "d MMMM y 'г'.", "yMMMEd": "EEE, d MMM y 'г'.", "yMMM": "LLL y 'г'.", "yMMMM": "LLLL y 'г'.",
+			"Md": "dd.MM", "MMMd": "d MMM", "MMMMd": "d MMMM", "MMMEd": "EEE, d MMM",
+			"Hm": "HH:mm", "Hms": "HH:mm:ss", "hm": "h:mm a", "hms": "h:mm:ss a",
+		},
+		relative: map[string]relativeUnit{
+			"second": russianRelative("секунду", "секунды", "секунд"),
+			"minute": russianRelative("минуту", "минуты", "минут"),
+			"hour":   russianRelative("час", "часа", "часов"),
+			"day":    russianRelative("день", "дня", "дней"),
+			"week":   russianRelative("неделю", "недели", "недель"),
+			"month":  russianRelative("месяц", "месяца", "месяцев"),
+			"year":   russianRelative("год", "года", "лет"),
+		},
+		now: "сейчас",
+	},
+}
+
+// russianRelative builds the relative patterns of a unit in Russian, with the
+// names of the one, few and many forms.
+func russianRelative(one, few, many string) relativeUnit {
+	forms := func(pattern string) map[string]string {
+		return map[string]string{
+			"one":   strings.Replace(pattern, "%s", one, 1),
+			"few":   strings.Replace(pattern, "%s", few, 1),
+			"many":  strings.Replace(pattern, "%s", many, 1),
+			"other": strings.Replace(pattern, "%s", few, 1),
+		}
+	}
+	return relativeUnit{past: forms("{0} %s назад"), future: forms("через {0} %s")}
+}
@@ -0,0 +1,79 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatTime(t *testing.T) {
+	tm := time.Date(2024, time.March, 5, 14, 7, 9, 0, time.UTC)
+	for _, item := range []struct {
+		lang, typ, style string
+		val              interface{}
+		result           string
+	}{
+		{"en", "date", "", tm, "Mar 5, 2024"},
+		{"en", "date", "short", tm, "3/5/24"},
+		{"en", "date", "full", tm, "Tuesday, March 5, 2024"},
+		{"en", "time", "short", tm, "2:07 PM"},
+		{"en", "time", "long", tm, "2:07:09 PM UTC"},
+		{"en", "", "", tm, "3/5/24, 2:07 PM"},
+		{"de", "date", "long", tm, "5. März 2024"},
+		{"de", "time", "", tm, "14:07:09"},
+		{"fr", "date", "full", tm, "mardi 5 mars 2024"},
+		{"es", "date", "long", tm, "5 de marzo de 2024"},
+		{"zh", "date", "long", tm, "2024年3月5日"},
+		{"zh-Hant", "time", "::hm", tm, "下午2:07"},
+		{"ja", "date", "::MMMEd", tm, "3月5日(火)"},
+		{"ko", "date", "medium", tm, "2024. 3. 5."},
+		{"ru", "date", "::yMMMM", tm, "март 2024 г."},
+		{"ru", "date", "long", tm, "5 марта 2024 г."},
+		{"en", "date", "yyyy-MM-dd'T'HH:mm 'o''clock'", tm, "2024-03-05T14:07 o'clock"},
+		{"xx", "date", "medium", tm, "Mar 5, 2024"},
+		{"en", "date", "", "today", "today"},
+		{"en", "", "", -3 * 24 * time.Hour, "3 days ago"},
+		{"en", "relative", "", 90 * time.Minute, "in 1 hour"},
+		{"en", "relative", "minute", 90 * time.Minute, "in 90 minutes"},
+		{"en", "relative", "", time.Duration(0), "now"},
+		{"de", "", "", -24 * time.Hour, "vor 1 Tag"},
+		{"fr", "", "", 2 * 7 * 24 * time.Hour, "dans 2 semaines"},
+		{"zh", "", "", -5 * time.Second, "5秒钟前"},
+		{"ru", "", "", -5 * 365 * 24 * time.Hour, "5 лет назад"},
+		{"ru", "", "", 22 * time.Hour, "через 22 часа"},
+		{"en", "relative", "", -100 * 365 * 24 * time.Hour, "100 years ago"},
+	} {
+		res, err := formatTime(item.lang, nil, item.val, item.typ, item.style)
+		assert.Nil(t, err)
+		assert.Equal(t, item.result, res, item)
+	}
+
+	// Test the time zone and the time relative to now.
+	loc := time.FixedZone("CST", 8*3600)
+	res, err := formatTime("en", loc, tm, "time", "short")
+	assert.Nil(t, err)
+	assert.Equal(t, "10:07 PM", res)
+	res, err = formatTime("en", nil, time.Now().Add(-49*time.Hour), "relative", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "2 days ago", res)
+
+	_, err = formatTime("en", nil, tm, "date", "::yQQQ")
+	assert.True(t, errors.Is(err, ErrInvalidICUFormat))
+	_, err = formatTime("en", nil, time.Hour, "relative", "decade")
+	assert.True(t, errors.Is(err, ErrInvalidICUFormat))
+}
+
+func TestFormatTimeArguments(t *testing.T) {
+	c, err := NewClient(1, 2, WithFetcher(&mockFetcher{}))
+	assert.Nil(t, err)
+	defer c.Shutdown(context.TODO())
+
+	tm := time.Date(2024, time.March, 5, 14, 7, 9, 0, time.UTC)
+	vars := map[string]interface{}{"d": tm, "ago": -2 * time.Hour}
+	res, err := c.processVars("{d, date, long} {d, time, short} ({ago})", "de", time.FixedZone("CET", 3600), false, vars, "", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "5. März 2024 15:07 (vor 2 Stunden)", res)
+}
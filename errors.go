@@ -0,0 +1,112 @@
+package i18n
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BatchError collects the errors of a batch of operations which are keyed by
+// the item of the operation, such as the language of the package.
+type BatchError struct {
+	Errors map[string]error
+}
+
+// Error implements the `error` interface.
+func (e *BatchError) Error() string {
+	keys := make([]string, 0, len(e.Errors))
+	for k := range e.Errors {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	msgs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		msgs = append(msgs, k+": "+e.Errors[k].Error())
+	}
+	return strconv.Itoa(len(keys)) + " operations failed: " + strings.Join(msgs, "; ")
+}
+
+// Error is the error of retrieving a text or package with where it happens,
+// which wraps the cause like `ErrKeyNotExist` for `errors.Is` and `errors.As`.
+type Error struct {
+	ProjectID   int64
+	NamespaceID int64
+	Env         string
+	Language    string
+	Key         string // empty if retrieving a package
+	Version     string // the release version, or "latest"
+	Err         error
+}
+
+// Error implements the `error` interface.
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Err.Error())
+	b.WriteString(": project=" + strconv.FormatInt(e.ProjectID, 10))
+	b.WriteString(" namespace=" + strconv.FormatInt(e.NamespaceID, 10))
+	b.WriteString(" env=" + e.Env)
+	b.WriteString(" language=" + e.Language)
+	if len(e.Key) != 0 {
+		b.WriteString(" key=" + e.Key)
+	}
+	b.WriteString(" version=" + e.Version)
+	return b.String()
+}
+
+// Unwrap returns the cause of the error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// FormatError is the error of an ICU message at the byte offset Pos, which
+// wraps `ErrInvalidICUFormat`.
+type FormatError struct {
+	Pos int
+	Msg string
+}
+
+// Error implements the `error` interface.
+func (e *FormatError) Error() string {
+	return ErrInvalidICUFormat.Error() + ": " + e.Msg + " at " + strconv.Itoa(e.Pos)
+}
+
+// Unwrap returns `ErrInvalidICUFormat`.
+func (e *FormatError) Unwrap() error {
+	return ErrInvalidICUFormat
+}
+
+// wrapError wraps the error of a request with the context of the option, the
+// text result gives the namespace and version where the text is found.
+func (c *client) wrapError(o *option, lang, key string, res TextResult, err error) error {
+	var e *Error
+	if err == nil || errors.As(err, &e) {
+		return err
+	}
+	e = &Error{
+		ProjectID:   o.projectID,
+		NamespaceID: o.namespaceID,
+		Env:         o.env,
+		Language:    lang,
+		Key:         key,
+		Version:     o.version,
+		Err:         err,
+	}
+	if e.ProjectID == 0 {
+		e.ProjectID = c.projectID
+	}
+	if res.NamespaceID != 0 {
+		e.NamespaceID = res.NamespaceID
+	} else if e.NamespaceID == 0 {
+		e.NamespaceID = c.namespaceID
+	}
+	if len(e.Env) == 0 {
+		e.Env = EnvNormal
+	}
+	if len(res.ReleaseVersion) != 0 {
+		e.Version = res.ReleaseVersion
+	} else if len(e.Version) == 0 {
+		e.Version = "latest"
+	}
+	return e
+}
@@ -0,0 +1,44 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError(t *testing.T) {
+	c, err := NewClient(1, 2, WithFetcher(&mockFetcher{}), WithOverrides(NewStaticOverrides(Override{Texts: map[string]string{
+		"broken": "Hello {n, plural, one {# item}",
+	}})))
+	assert.Nil(t, err)
+	defer c.Shutdown(context.TODO())
+
+	_, err = c.GetText(context.TODO(), "en", "not-exist-key")
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, &Error{ProjectID: 1, NamespaceID: 2, Env: EnvNormal, Language: "en", Key: "not-exist-key",
+		Version: "latest", Err: ErrKeyNotExist}, e)
+	assert.EqualError(t, err, "given key not exist: project=1 namespace=2 env=normal language=en key=not-exist-key version=latest")
+
+	_, err = c.GetText(context.TODO(), "en", "key1", WithPluralCount(1))
+	assert.True(t, errors.Is(err, ErrInvalidICUFormat))
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, "1.2", e.Version)
+
+	_, err = c.GetText(context.TODO(), "en", "broken", WithPluralCount(1))
+	var fe *FormatError
+	assert.True(t, errors.As(err, &fe))
+	assert.Equal(t, 6, fe.Pos)
+	assert.EqualError(t, fe, "invalid ICU format string: unclosed '{' at 6")
+	assert.True(t, errors.Is(err, ErrInvalidICUFormat))
+
+	_, err = c.GetPackage(context.TODO(), "INVALID")
+	assert.True(t, errors.Is(err, ErrBackToSourceFailed))
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, "", e.Key)
+
+	_, err = NewHttpFetcher().Fetch(context.TODO(), 1, 2, "en")
+	assert.True(t, errors.Is(err, ErrNoAppKey))
+}
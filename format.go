@@ -0,0 +1,215 @@
+package i18n
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+var (
+	// printers caches the message printer of each language.
+	printers sync.Map // language -> *message.Printer
+
+	// numberLocales is the currency and compact data of the languages, taken
+	// from CLDR. The other languages fall back to `en`.
+	numberLocales = map[string]*numberLocale{
+		"en": {currency: "{1}{0}", compact: []compactUnit{
+			{1e12, 1e12, "T"}, {1e9, 1e9, "B"}, {1e6, 1e6, "M"}, {1e3, 1e3, "K"}}},
+		"zh": {currency: "{1}{0}", compact: []compactUnit{
+			{1e12, 1e12, "万亿"}, {1e8, 1e8, "亿"}, {1e4, 1e4, "万"}}},
+		"zh-Hant": {currency: "{1}{0}", compact: []compactUnit{
+			{1e12, 1e12, "兆"}, {1e8, 1e8, "億"}, {1e4, 1e4, "萬"}}},
+		"ja": {currency: "{1}{0}", compact: []compactUnit{
+			{1e12, 1e12, "兆"}, {1e8, 1e8, "億"}, {1e4, 1e4, "万"}}},
+		"ko": {currency: "{1}{0}", compact: []compactUnit{
+			{1e12, 1e12, "조"}, {1e8, 1e8, "억"}, {1e4, 1e4, "만"}, {1e3, 1e3, "천"}}},
+		"de": {currency: "{0}\u00a0{1}", compact: []compactUnit{
+			{1e12, 1e12, "\u00a0Bio."}, {1e9, 1e9, "\u00a0Mrd."}, {1e6, 1e6, "\u00a0Mio."}}},
+		"fr": {currency: "{0}\u00a0{1}", compact: []compactUnit{
+			{1e12, 1e12, "\u00a0Bn"}, {1e9, 1e9, "\u00a0Md"}, {1e6, 1e6, "\u00a0M"}, {1e3, 1e3, "\u00a0k"}}},
+		"es": {currency: "{0}\u00a0{1}", compact: []compactUnit{
+			{1e12, 1e12, "\u00a0B"}, {1e9, 1e6, "\u00a0M"}, {1e6, 1e6, "\u00a0M"}, {1e3, 1e3, "\u00a0mil"}}},
+		"pt": {currency: "{1}\u00a0{0}", compact: []compactUnit{
+			{1e12, 1e12, "\u00a0tri"}, {1e9, 1e9, "\u00a0bi"}, {1e6, 1e6, "\u00a0mi"}, {1e3, 1e3, "\u00a0mil"}}},
+		"pt-PT": {currency: "{0}\u00a0{1}", compact: []compactUnit{
+			{1e12, 1e12, "\u00a0Bi"}, {1e9, 1e9, "\u00a0mM"}, {1e6, 1e6, "\u00a0M"}, {1e3, 1e3, "\u00a0mil"}}},
+		"ru": {currency: "{0}\u00a0{1}", compact: []compactUnit{
+			{1e12, 1e12, "\u00a0трлн"}, {1e9, 1e9, "\u00a0млрд"}, {1e6, 1e6, "\u00a0млн"}, {1e3, 1e3, "\u00a0тыс."}}},
+	}
+)
+
+// numberLocale is the number data of a language, the currency pattern puts
+// the amount at {0} and the symbol at {1}.
+type numberLocale struct {
+	currency string
+	compact  []compactUnit
+}
+
+// compactUnit is a unit of the short compact notation from the value, whose
+// numbers are divided by the divisor, e.g. "1234 M" for 1.234e9 in `es`.
+type compactUnit struct {
+	value, divisor float64
+	suffix         string
+}
+
+// numberLocaleOf returns the number data of the language by its tag, script
+// and base in order, or the data of `en` if it is not found.
+func numberLocaleOf(lang string) *numberLocale {
+	tag := language.Make(lang)
+	base, _ := tag.Base()
+	script, _ := tag.Script()
+	for _, name := range []string{tag.String(), base.String() + "-" + script.String(), base.String()} {
+		if l, ok := numberLocales[name]; ok {
+			return l
+		}
+	}
+	return numberLocales["en"]
+}
+
+func printer(lang string) *message.Printer {
+	if p, ok := printers.Load(lang); ok {
+		return p.(*message.Printer)
+	}
+	p := message.NewPrinter(language.Make(lang))
+	printers.Store(lang, p)
+	return p
+}
+
+// formatArg formats the argument `name[, type[, style]]` in the ICU style,
+// the missing argument is formatted as an empty string. The numbers typed as
+// `number`, times, durations and lists are formatted according to the
+// language, the times are in the location if it is not nil, and the other
+// values including the bare numbers are formatted with `%v`. The result is
+// HTML escaped unless it is `template.HTML`.
+func formatArg(lang string, loc *time.Location, arg string, vars map[string]interface{}) (string, error) {
+	parts := strings.SplitN(arg, ",", 3)
+	var typ, style string
+	if len(parts) > 1 {
+		typ = strings.TrimSpace(parts[1])
+	}
+	if len(parts) > 2 {
+		style = strings.TrimSpace(parts[2])
+	}
+	return formatValue(lang, loc, vars, strings.TrimSpace(parts[0]), typ, style)
+}
+
+// formatValue formats the argument of the name with the parsed type and style,
+// see `formatArg` for details.
+func formatValue(lang string, loc *time.Location, vars map[string]interface{}, name, typ, style string) (string, error) {
+	val, ok := vars[name]
+	if !ok {
+		return "", nil
+	}
+	if html, ok := val.(template.HTML); ok {
+		return string(html), nil
+	}
+
+	var text string
+	switch {
+	case typ == "number":
+		var err error
+		if text, err = formatNumber(lang, val, style); err != nil {
+			return "", err
+		}
+	case typ == "list" || len(typ) == 0 && isList(val):
+		var err error
+		if text, err = formatList(lang, val, style); err != nil {
+			return "", err
+		}
+	case typ == "date" || typ == "time" || typ == "relative" || len(typ) == 0 && isTime(val):
+		var err error
+		if text, err = formatTime(lang, loc, val, typ, style); err != nil {
+			return "", err
+		}
+	default:
+		text = fmt.Sprintf("%v", val)
+	}
+	return template.HTMLEscapeString(text), nil
+}
+
+// formatNumber formats the number with the ICU number style, which is one of
+// "integer", "percent", "currency/<ISO code>" and "compact", the decimal
+// format is used if the style is empty. The value which is not a number is
+// formatted with `%v`.
+func formatNumber(lang string, val interface{}, style string) (string, error) {
+	if s, ok := val.(string); ok {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return s, nil
+		}
+		val = f
+	}
+	if !isNumber(val) {
+		return fmt.Sprintf("%v", val), nil
+	}
+	p := printer(lang)
+	style = strings.TrimPrefix(style, "::")
+	switch {
+	case len(style) == 0:
+		return p.Sprint(number.Decimal(val)), nil
+	case style == "integer":
+		return p.Sprint(number.Decimal(val, number.MaxFractionDigits(0))), nil
+	case style == "percent":
+		return p.Sprint(number.Percent(val)), nil
+	case strings.HasPrefix(style, "currency/"):
+		return formatCurrency(p, lang, val, strings.TrimPrefix(style, "currency/"))
+	case style == "compact" || style == "compact-short":
+		return formatCompact(p, lang, val), nil
+	}
+	return "", fmt.Errorf("%w: unknown number style %q", ErrInvalidICUFormat, style)
+}
+
+// formatCurrency formats the amount with the standard digits of the currency
+// and its symbol by the CLDR currency pattern of the language.
+func formatCurrency(p *message.Printer, lang string, val interface{}, code string) (string, error) {
+	unit, err := currency.ParseISO(code)
+	if err != nil {
+		return "", fmt.Errorf("%w: unknown currency %q", ErrInvalidICUFormat, code)
+	}
+	l := numberLocaleOf(lang)
+	scale, _ := currency.Standard.Rounding(unit)
+	amount := p.Sprint(number.Decimal(val, number.Scale(scale)))
+	sign := ""
+	if strings.HasPrefix(amount, "-") {
+		sign, amount = "-", amount[1:]
+	}
+	return sign + strings.NewReplacer("{0}", amount, "{1}", p.Sprint(currency.Symbol(unit))).Replace(l.currency), nil
+}
+
+// formatCompact formats the number in the short compact notation of the
+// language, e.g. 1.2M in `en` and 1,2 Mio. in `de`.
+func formatCompact(p *message.Printer, lang string, val interface{}) string {
+	l := numberLocaleOf(lang)
+	f, _ := toFloat(val)
+	for _, u := range l.compact {
+		if math.Abs(f) >= u.value {
+			return p.Sprint(number.Decimal(f/u.divisor, number.MaxFractionDigits(1))) + u.suffix
+		}
+	}
+	return p.Sprint(number.Decimal(val, number.MaxFractionDigits(1)))
+}
+
+func isNumber(val interface{}) bool {
+	switch val.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return true
+	}
+	return false
+}
+
+func isTime(val interface{}) bool {
+	switch val.(type) {
+	case time.Time, time.Duration:
+		return true
+	}
+	return false
+}
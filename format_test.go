@@ -0,0 +1,81 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatNumber(t *testing.T) {
+	for _, item := range []struct {
+		lang, style string
+		val         interface{}
+		result      string
+	}{
+		{"en", "", 1234567, "1,234,567"},
+		{"de", "", 1234567.5, "1.234.567,5"},
+		{"en", "", "1234", "1,234"},
+		{"en", "", "n/a", "n/a"},
+		{"en", "integer", 1234.4, "1,234"},
+		{"en", "percent", 0.3, "30%"},
+		{"de", "percent", 0.3, "30\u00a0%"},
+		{"en", "currency/EUR", 1234.5, "€1,234.50"},
+		{"de", "currency/EUR", 1234.5, "1.234,50\u00a0€"},
+		{"ja", "currency/JPY", 1234, "￥1,234"},
+		{"pt", "currency/BRL", 1234.5, "R$\u00a01.234,50"},
+		{"en", "currency/USD", -5, "-$5.00"},
+		{"en", "compact", 1234567, "1.2M"},
+		{"en", "::compact-short", 999, "999"},
+		{"zh", "compact", 123456, "12.3万"},
+		{"zh-TW", "compact", 123456, "12.3萬"},
+		{"de", "compact", 1234567, "1,2\u00a0Mio."},
+		{"de", "compact", 1234, "1.234"},
+		{"fr", "compact", 1234567, "1,2\u00a0M"},
+		{"ru", "compact", 1234567, "1,2\u00a0млн"},
+		{"es", "compact", 1234567, "1,2\u00a0M"},
+	} {
+		res, err := formatNumber(item.lang, item.val, item.style)
+		assert.Nil(t, err)
+		assert.Equal(t, item.result, res, item)
+	}
+
+	_, err := formatNumber("en", 1, "currency/XXXX")
+	assert.True(t, errors.Is(err, ErrInvalidICUFormat))
+	_, err = formatNumber("en", 1, "scientific")
+	assert.True(t, errors.Is(err, ErrInvalidICUFormat))
+	// The languages without the CLDR data fall back to the patterns of English.
+	res, err := formatNumber("it", 1234567, "compact")
+	assert.Nil(t, err)
+	assert.Equal(t, "1,2M", res)
+	res, err = formatNumber("nl", 1234.5, "currency/EUR")
+	assert.Nil(t, err)
+	assert.Equal(t, "€1.234,50", res)
+}
+
+func TestFormatArguments(t *testing.T) {
+	c, err := NewClient(1, 2, WithFetcher(&mockFetcher{}))
+	assert.Nil(t, err)
+	defer c.Shutdown(context.TODO())
+
+	vars := map[string]interface{}{
+		"n": 1234567, "rate": 0.3, "price": 9.5, "name": "<Tom>", "tag": template.HTML("<b>x</b>"),
+	}
+	// The bare numbers like years and IDs are never grouped.
+	res, err := c.processVars("{n} | {n, number} | {n, number, compact} | {rate, number, percent} | {price, number, currency/EUR} | {name} | {tag} | {none}", "fr", nil, false, vars, "", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "1234567 | 1\u00a0234\u00a0567 | 1,2\u00a0M | 30\u00a0% | 9,50\u00a0€ | &lt;Tom&gt; | <b>x</b> | ", res)
+	res, err = c.processVars("[[ n , number, integer ]] [[n", "de", nil, false, vars, "[[", "]]")
+	assert.Nil(t, err)
+	assert.Equal(t, "1.234.567 [[n", res)
+	_, err = c.processVars("{n, number, bad}", "en", nil, false, vars, "", "")
+	assert.True(t, errors.Is(err, ErrInvalidICUFormat))
+
+	// Test the plural count formatted according to the language.
+	text, err := c.GetText(context.TODO(), "de", "key5", WithPluralCount(1234),
+		WithArguments(map[string]interface{}{"farm": "ByteDance"}))
+	assert.Nil(t, err)
+	assert.Equal(t, "I have 1.234 apples from ByteDance.", text)
+}
@@ -0,0 +1,85 @@
+package i18n
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Invoker sends the HTTP request to the starling server and returns the response.
+type Invoker func(req *http.Request) (*http.Response, error)
+
+// Interceptor wraps the outbound HTTP requests of the http fetcher. It can
+// mutate the request before calling next, inspect the response returned by
+// next, or short-circuit the request by returning without calling next.
+type Interceptor func(req *http.Request, next Invoker) (*http.Response, error)
+
+// chainInterceptors builds an invoker which calls the interceptors in order
+// and the given invoker at last.
+func chainInterceptors(invoker Invoker, interceptors ...Interceptor) Invoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(req *http.Request) (*http.Response, error) {
+			return interceptor(req, next)
+		}
+	}
+	return invoker
+}
+
+// NewHeaderInterceptor creates an interceptor which sets the given headers on
+// each outbound request, such as request ids, tenant markers or service name.
+func NewHeaderInterceptor(headers map[string]string) Interceptor {
+	return func(req *http.Request, next Invoker) (*http.Response, error) {
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		return next(req)
+	}
+}
+
+// NewLogInterceptor creates an interceptor which writes a summary of each
+// request and response to the logger with the credentials redacted.
+func NewLogInterceptor(logger Logger) Interceptor {
+	logger = withRedaction(logger)
+	return func(req *http.Request, next Invoker) (*http.Response, error) {
+		begin := time.Now()
+		resp, err := next(req)
+		logger.Info("http round trip: req=%s, resp=%s, elapsed=%v, err=%v",
+			describeRequest(req), describeResponse(resp), time.Since(begin), err)
+		return resp, err
+	}
+}
+
+// describeRequest summarizes the request without the sensitive headers.
+func describeRequest(req *http.Request) string {
+	if req == nil {
+		return "<nil>"
+	}
+	s := req.Method + " " + req.URL.String()
+	for k, v := range req.Header {
+		var val string
+		if isSensitiveHeader(k) {
+			val = "***"
+		} else if len(v) != 0 {
+			val = v[0]
+		}
+		s += " " + k + "=" + val
+	}
+	return s
+}
+
+// describeResponse summarizes the response with its status and size.
+func describeResponse(resp *http.Response) string {
+	if resp == nil {
+		return "<nil>"
+	}
+	return resp.Status + " size=" + strconv.FormatInt(resp.ContentLength, 10)
+}
+
+func isSensitiveHeader(key string) bool {
+	switch http.CanonicalHeaderKey(key) {
+	case "Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package i18n
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newPackageServer(t *testing.T, handler func(r *http.Request)) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if handler != nil {
+			handler(r)
+		}
+		gw := gzip.NewWriter(w)
+		defer gw.Close()
+		_, err := gw.Write([]byte(`{"version":"1","release_version":"1.0","language":"en","data":{"k":"v"}}`))
+		assert.Nil(t, err)
+	}))
+}
+
+func TestInterceptors(t *testing.T) {
+	var header http.Header
+	srv := newPackageServer(t, func(r *http.Request) { header = r.Header })
+	defer srv.Close()
+
+	var order []string
+	trace := func(name string) Interceptor {
+		return func(req *http.Request, next Invoker) (*http.Response, error) {
+			order = append(order, name)
+			return next(req)
+		}
+	}
+	p := NewHttpFetcher(
+		WithHTTPDomain(strings.TrimPrefix(srv.URL, "http://")),
+		WithAppKey("app12345"),
+		WithDisableBackupStorage(true),
+		WithInterceptors(trace("fetcher"), NewHeaderInterceptor(map[string]string{"X-Request-ID": "rid"})))
+
+	pkg, err := p.Fetch(context.TODO(), 1, 2, "en", WithInterceptors(trace("request")))
+	assert.Nil(t, err)
+	assert.Equal(t, "v", pkg.Data["k"])
+	assert.Equal(t, []string{"fetcher", "request"}, order)
+	assert.Equal(t, "rid", header.Get("X-Request-ID"))
+	assert.NotEmpty(t, header.Get("Authorization"))
+
+	// Test short-circuit without sending the request.
+	header = nil
+	body := &bytes.Buffer{}
+	gw := gzip.NewWriter(body)
+	gw.Write([]byte(`{"language":"de","data":{"k":"cached"}}`))
+	gw.Close()
+	pkg, err = p.Fetch(context.TODO(), 1, 2, "de", WithInterceptors(
+		func(req *http.Request, next Invoker) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(body)}, nil
+		}))
+	assert.Nil(t, err)
+	assert.Equal(t, "cached", pkg.Data["k"])
+	assert.Nil(t, header)
+
+	// Test short-circuit with neither a response nor an error.
+	nop := WithInterceptors(func(req *http.Request, next Invoker) (*http.Response, error) {
+		return nil, nil
+	})
+	_, err = p.Fetch(context.TODO(), 1, 2, "en", nop)
+	assert.True(t, errors.Is(err, errNilResponse))
+	_, _, err = p.FetchVersion(context.TODO(), 1, 2, "en", nop)
+	assert.True(t, errors.Is(err, errNilResponse))
+	assert.Nil(t, header)
+}
+
+func TestClientInterceptors(t *testing.T) {
+	srv := newPackageServer(t, nil)
+	defer srv.Close()
+
+	var calls int32
+	c, err := NewClient(1, 2,
+		WithHTTPDomain(strings.TrimPrefix(srv.URL, "http://")),
+		WithAppKey("app12345"),
+		WithDisableBackupStorage(true),
+		WithInterceptors(func(req *http.Request, next Invoker) (*http.Response, error) {
+			atomic.AddInt32(&calls, 1)
+			return next(req)
+		}))
+	assert.Nil(t, err)
+	defer c.Shutdown(context.TODO())
+
+	text, err := c.GetText(context.TODO(), "en", "k")
+	assert.Nil(t, err)
+	assert.Equal(t, "v", text)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestDescribeRequest(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost/api", nil)
+	req.Header.Set("Authorization", "secret-token")
+	req.Header.Set("X-Host-IP", "127.0.0.1")
+	s := describeRequest(req)
+	assert.NotContains(t, s, "secret-token")
+	assert.Contains(t, s, "Authorization=***")
+	assert.Contains(t, s, "X-Host-Ip=127.0.0.1")
+	assert.Equal(t, "<nil>", describeRequest(nil))
+	assert.Equal(t, "<nil>", describeResponse(nil))
+}
+
+func TestClientAddOptionFetcher(t *testing.T) {
+	var auth atomic.Value
+	srv := newPackageServer(t, func(r *http.Request) { auth.Store(r.Header.Get("Authorization")) })
+	defer srv.Close()
+
+	c, err := NewClient(1, 2, WithAppKey("app-key-0001"), WithDisableBackupStorage(true))
+	assert.Nil(t, err)
+	defer c.Shutdown(context.TODO())
+
+	// The default fetcher follows the domain and app key added at runtime.
+	c.AddOption(WithHTTPDomain(strings.TrimPrefix(srv.URL, "http://")), WithAppKey("app-key-0002"))
+	text, err := c.GetText(context.TODO(), "en", "k")
+	assert.Nil(t, err)
+	assert.Equal(t, "v", text)
+	parts := strings.Split(auth.Load().(string), ".")
+	assert.Equal(t, 3, len(parts))
+	assert.Equal(t, calcSignature(parts[0], parts[1], "app-key-0002"), parts[2])
+}
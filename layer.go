@@ -0,0 +1,94 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+)
+
+const (
+	// SourceRemote means the text comes from the package fetched from the server.
+	SourceRemote = "remote"
+	// SourceOverride means the text comes from the local `OverrideSource`.
+	SourceOverride = "override"
+	// SourceDefault means the key is missing and the text is the default
+	// message given by `WithDefaultMessage`.
+	SourceDefault = "default"
+	// SourceMissing means the key is missing and the text is returned by the
+	// `MissingKeyHandler`.
+	SourceMissing = "missing"
+)
+
+// TextResult is the text retrieved by `ExplainText` along with where it
+// comes from, which is useful to debug the namespace stack.
+type TextResult struct {
+	Text           string `json:"text"`
+	Key            string `json:"key"`
+	Language       string `json:"language"`
+	ProjectID      int64  `json:"project_id"`
+	NamespaceID    int64  `json:"namespace_id"`
+	Env            string `json:"env"`
+	Version        string `json:"version"`
+	ReleaseVersion string `json:"release_version"`
+	// Layer is the index of the namespace in the namespace stack which
+	// answered the key, which is always 0 without the namespace stack.
+	Layer int `json:"layer"`
+	// Source is where the text comes from, such as `SourceRemote`.
+	Source string `json:"source"`
+}
+
+// layer is a package of a namespace in the namespace stack, the package is nil
+// if it failed to fetch.
+type layer struct {
+	pkg         *Package
+	err         error
+	namespaceID int64
+	index       int
+}
+
+// getLayers returns the packages of the namespace stack in order, or the
+// package of the single namespace if the stack is not set. The failed layers are
+// kept for the overrides, and the first error is returned if all of them failed.
+func (c *client) getLayers(ctx context.Context, o *option, lang string, opts ...Option) ([]layer, error) {
+	var stack []int64
+	peekOptions(c.loadOptions(), opts, func(o *option) { stack = o.namespaceStack })
+	if len(stack) == 0 {
+		pkg, err := c.getPackage(ctx, o, lang, opts...)
+		return []layer{{pkg: pkg, err: err, namespaceID: o.namespaceID}}, err
+	}
+
+	layers := make([]layer, 0, len(stack))
+	var firstErr error
+	failed := 0
+	for i, nid := range stack {
+		// Each layer is fetched with its own option, so the option of the
+		// request keeps its namespace.
+		lo := op.get()
+		pkg, err := c.getPackage(ctx, lo, lang, append(opts[:len(opts):len(opts)], WithNamespaceID(nid))...)
+		if err != nil {
+			logWith(lo.logger, LevelWarn, "starling: skip failed namespace layer",
+				lo.fields(Field{"layer", i}, Field{"error", err})...)
+			if firstErr == nil {
+				firstErr = err
+			}
+			failed++
+		}
+		op.put(lo)
+		layers = append(layers, layer{pkg: pkg, err: err, namespaceID: nid, index: i})
+	}
+	// The errors of the option are returned by the layers already.
+	_, _ = c.handleOptions(o, lang, opts...)
+	if failed == len(layers) {
+		return layers, firstErr
+	}
+	return layers, nil
+}
+
+// lookupLayers returns the layers of the request, which fail only if there is no
+// override source to fall back on.
+func (c *client) lookupLayers(ctx context.Context, o *option, lang string, opts ...Option) ([]layer, error) {
+	layers, err := c.getLayers(ctx, o, lang, opts...)
+	if err != nil && (o.override == nil || errors.Is(err, ErrClientClosed)) {
+		return nil, err
+	}
+	return layers, nil
+}
@@ -0,0 +1,84 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// layerFetcher returns the texts of each namespace, and fails the unknown ones.
+type layerFetcher struct {
+	mockFetcher
+	data map[int64]map[string]string
+}
+
+func (l *layerFetcher) Fetch(ctx context.Context, pid, nid int64, lang string, opts ...Option) (*Package, error) {
+	data, ok := l.data[nid]
+	if !ok {
+		return nil, ErrBackToSourceFailed
+	}
+	pkg, err := l.mockFetcher.Fetch(ctx, pid, nid, lang, opts...)
+	if err != nil {
+		return nil, err
+	}
+	pkg.Data = data
+	return pkg, nil
+}
+
+func TestNamespaceStack(t *testing.T) {
+	f := &layerFetcher{data: map[int64]map[string]string{
+		2: {"title": "Product", "greet": "Hi {Name}"},
+		3: {"title": "Shared", "ok": "OK"},
+	}}
+	c, err := NewClient(1, 2, WithFetcher(f), WithNamespaceStack(2, 9, 3))
+	assert.Nil(t, err)
+	defer c.Shutdown(context.TODO())
+
+	text, err := c.GetText(context.TODO(), "en", "title")
+	assert.Nil(t, err)
+	assert.Equal(t, "Product", text)
+	text, err = c.GetText(context.TODO(), "en", "greet", WithArguments(map[string]interface{}{"Name": "Bob"}))
+	assert.Nil(t, err)
+	assert.Equal(t, "Hi Bob", text)
+
+	res, err := c.ExplainText(context.TODO(), "en", "ok")
+	assert.Nil(t, err)
+	assert.Equal(t, &TextResult{
+		Text:           "OK",
+		Key:            "ok",
+		Language:       "en",
+		ProjectID:      1,
+		NamespaceID:    3,
+		Env:            EnvNormal,
+		Version:        "13",
+		ReleaseVersion: "1.3",
+		Layer:          2,
+		Source:         SourceRemote,
+	}, res)
+
+	_, err = c.GetText(context.TODO(), "en", "none")
+	assert.True(t, errors.Is(err, ErrKeyNotExist))
+	// The missing key is reported with the namespace of the request rather
+	// than the last layer.
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, int64(2), e.NamespaceID)
+	texts, err := c.GetTexts(context.TODO(), "en", []string{"title", "ok", "none"})
+	assert.Equal(t, map[string]string{"title": "Product", "ok": "OK"}, texts)
+	assert.True(t, errors.Is(err.(*BatchError).Errors["none"], ErrKeyNotExist))
+
+	// Test the stack overridden by the request and all the layers failed.
+	text, err = c.GetText(context.TODO(), "en", "title", WithNamespaceStack(3, 2))
+	assert.Nil(t, err)
+	assert.Equal(t, "Shared", text)
+	_, err = c.GetText(context.TODO(), "en", "title", WithNamespaceStack(8, 9))
+	assert.True(t, errors.Is(err, ErrBackToSourceFailed))
+
+	// Test the client without the stack.
+	res, err = c.ExplainText(context.TODO(), "en", "title", WithNamespaceStack())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), res.NamespaceID)
+	assert.Equal(t, 0, res.Layer)
+}
@@ -0,0 +1,392 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// The severities of the lint issues.
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+)
+
+// The rules of the lint issues.
+const (
+	// LintRuleSyntax is the invalid ICU message syntax.
+	LintRuleSyntax = "syntax"
+	// LintRulePluralOther is the plural or select argument without `other`.
+	LintRulePluralOther = "plural-other"
+	// LintRulePluralCategory is the plural category required by the CLDR rules
+	// of the language but missing, or the unknown plural selector.
+	LintRulePluralCategory = "plural-category"
+	// LintRulePlaceholder is the placeholder missing or not in the message of
+	// the source language.
+	LintRulePlaceholder = "placeholder"
+	// LintRuleDelimiter is the unbalanced custom delimiters.
+	LintRuleDelimiter = "delimiter"
+	// LintRuleHTML is the unbalanced HTML tags.
+	LintRuleHTML = "html"
+	// LintRuleMissing is the key in the source language but missing in the
+	// translation.
+	LintRuleMissing = "missing"
+)
+
+var (
+	pluralForms = []string{"other", "zero", "one", "two", "few", "many"}
+	htmlRegexp  = regexp.MustCompile(`<(/?)([a-zA-Z][a-zA-Z0-9-]*)[^<>]*?(/?)>`)
+	voidTags    = map[string]bool{
+		"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+		"input": true, "link": true, "meta": true, "param": true, "source": true, "track": true, "wbr": true,
+	}
+)
+
+// LintIssue is an issue of a message found by `Lint`.
+type LintIssue struct {
+	Language string `json:"language"`
+	Key      string `json:"key"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	// Pos is the byte offset of the issue in the message, or -1 if it is
+	// about the whole message.
+	Pos int `json:"pos"`
+}
+
+// String formats the issue as `language/key:pos: severity: message (rule)`.
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s/%s:%d: %s: %s (%s)", i.Language, i.Key, i.Pos, i.Severity, i.Message, i.Rule)
+}
+
+// LintReport is the machine-readable result of `Lint`.
+type LintReport struct {
+	SourceLanguage string      `json:"source_language"`
+	Packages       int         `json:"packages"`
+	Messages       int         `json:"messages"`
+	Errors         int         `json:"errors"`
+	Warnings       int         `json:"warnings"`
+	Issues         []LintIssue `json:"issues"`
+}
+
+func (r *LintReport) add(issue LintIssue) {
+	if issue.Severity == LintError {
+		r.Errors++
+	} else {
+		r.Warnings++
+	}
+	r.Issues = append(r.Issues, issue)
+}
+
+// Lint checks every message of the packages, including the ICU syntax, the
+// plural categories required by the CLDR rules of each language for the
+// integer counts, the placeholders matching the message of the source
+// language, and the balance of the custom delimiters and HTML tags. The
+// delimiters set by `WithLeftDelimiter` and `WithRightDelimiter` are checked
+// if given. The issues are sorted by the language and key.
+func Lint(source string, pkgs []*Package, opts ...Option) *LintReport {
+	o := &option{}
+	for _, f := range opts {
+		f(o)
+	}
+	l := &linter{left: o.leftDelimiter, right: o.rightDelimiter}
+	if l.left == defaultLeftDelimiter && l.right == defaultRightDelimiter {
+		l.left, l.right = "", ""
+	}
+
+	report := &LintReport{SourceLanguage: source, Packages: len(pkgs)}
+	var sourcePkg *Package
+	for _, pkg := range pkgs {
+		if pkg != nil && pkg.Language == source {
+			sourcePkg = pkg
+		}
+	}
+	for _, pkg := range pkgs {
+		if pkg == nil {
+			continue
+		}
+		keys := make([]string, 0, len(pkg.Data))
+		for k := range pkg.Data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			report.Messages++
+			var sourceText *string
+			if sourcePkg != nil && pkg != sourcePkg {
+				if text, ok := sourcePkg.Data[key]; ok {
+					sourceText = &text
+				}
+			}
+			for _, issue := range l.lint(pkg.Language, pkg.Data[key], sourceText) {
+				issue.Language, issue.Key = pkg.Language, key
+				report.add(issue)
+			}
+		}
+		if sourcePkg != nil && pkg != sourcePkg {
+			for key := range sourcePkg.Data {
+				if _, ok := pkg.Data[key]; !ok {
+					report.add(LintIssue{Language: pkg.Language, Key: key, Rule: LintRuleMissing,
+						Severity: LintWarning, Message: "missing translation", Pos: -1})
+				}
+			}
+		}
+	}
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		a, b := report.Issues[i], report.Issues[j]
+		if a.Language != b.Language {
+			return a.Language < b.Language
+		}
+		return a.Key < b.Key
+	})
+	return report
+}
+
+// linter checks a message with the custom delimiters if any.
+type linter struct {
+	left, right string
+}
+
+func (l *linter) lint(lang, text string, source *string) []LintIssue {
+	var issues []LintIssue
+	issue := func(rule, severity string, pos int, format string, v ...interface{}) {
+		issues = append(issues, LintIssue{Rule: rule, Severity: severity, Pos: pos, Message: fmt.Sprintf(format, v...)})
+	}
+
+	if len(l.left) != 0 {
+		if pos, msg := l.checkDelimiters(text); pos >= 0 {
+			issue(LintRuleDelimiter, LintError, pos, "%s", msg)
+		}
+	}
+	if pos, msg := checkHTML(text); pos >= 0 {
+		issue(LintRuleHTML, LintError, pos, "%s", msg)
+	}
+	nodes, err := parseMessage(l.mask(text))
+	if err != nil {
+		pos := -1
+		var merr *FormatError
+		if errors.As(err, &merr) {
+			pos = merr.Pos
+		}
+		issue(LintRuleSyntax, LintError, pos, "%v", err)
+		return issues
+	}
+
+	tag := language.Make(lang)
+	walkMessage(nodes, func(n msgNode) {
+		switch n.kind {
+		case nodeSelect:
+			if !hasSelector(n, "other") {
+				issue(LintRulePluralOther, LintError, n.pos, "select %q misses the other option", n.name)
+			}
+		case nodePlural, nodeSelectOrdinal:
+			if !hasSelector(n, "other") {
+				issue(LintRulePluralOther, LintError, n.pos, "plural %q misses the other option", n.name)
+			}
+			rules := plural.Cardinal
+			if n.kind == nodeSelectOrdinal {
+				rules = plural.Ordinal
+			}
+			for _, form := range requiredForms(rules, tag) {
+				if form != "other" && !hasSelector(n, form) {
+					issue(LintRulePluralCategory, LintWarning, n.pos, "plural %q misses the %s option required by %s", n.name, form, lang)
+				}
+			}
+			for _, opt := range n.options {
+				if !isPluralSelector(opt.selector) {
+					issue(LintRulePluralCategory, LintError, n.pos, "plural %q has an unknown selector %q", n.name, opt.selector)
+				}
+			}
+		}
+	})
+
+	if source == nil {
+		return issues
+	}
+	sourceNodes, err := parseMessage(l.mask(*source))
+	if err != nil {
+		return issues
+	}
+	want, got := l.placeholders(*source, sourceNodes), l.placeholders(text, nodes)
+	for _, name := range sortedKeys(want) {
+		if !got[name] {
+			issue(LintRulePlaceholder, LintError, -1, "placeholder %q is missing", name)
+		}
+	}
+	for _, name := range sortedKeys(got) {
+		if !want[name] {
+			issue(LintRulePlaceholder, LintError, -1, "placeholder %q is not in the source message", name)
+		}
+	}
+	return issues
+}
+
+// mask blanks the variables of the custom delimiters out, so that they do not
+// break the ICU syntax and the positions are kept.
+func (l *linter) mask(text string) string {
+	if len(l.left) == 0 || !strings.ContainsAny(l.left+l.right, "{}#'") {
+		return text
+	}
+	b := []byte(text)
+	for i := 0; i < len(b); {
+		start := strings.Index(text[i:], l.left)
+		if start < 0 {
+			break
+		}
+		start += i
+		end := strings.Index(text[start+len(l.left):], l.right)
+		if end < 0 {
+			break
+		}
+		end += start + len(l.left) + len(l.right)
+		for j := start; j < end; j++ {
+			b[j] = ' '
+		}
+		i = end
+	}
+	return string(b)
+}
+
+// checkDelimiters returns the position of the first unbalanced custom
+// delimiter, or -1 if they are balanced.
+func (l *linter) checkDelimiters(text string) (int, string) {
+	for i := 0; i < len(text); {
+		left := strings.Index(text[i:], l.left)
+		right := strings.Index(text[i:], l.right)
+		if left < 0 && right < 0 {
+			break
+		}
+		if left < 0 || (right >= 0 && right < left) {
+			return i + right, fmt.Sprintf("unmatched %q", l.right)
+		}
+		start := i + left + len(l.left)
+		end := strings.Index(text[start:], l.right)
+		if end < 0 {
+			return i + left, fmt.Sprintf("unclosed %q", l.left)
+		}
+		if next := strings.Index(text[start:start+end], l.left); next >= 0 {
+			return start + next, fmt.Sprintf("nested %q", l.left)
+		}
+		i = start + end + len(l.right)
+	}
+	return -1, ""
+}
+
+// placeholders returns the names of the ICU arguments and the variables of the
+// custom delimiters.
+func (l *linter) placeholders(text string, nodes []msgNode) map[string]bool {
+	names := make(map[string]bool)
+	walkMessage(nodes, func(n msgNode) {
+		if n.kind != nodeText && n.kind != nodePound {
+			names[n.name] = true
+		}
+	})
+	if len(l.left) == 0 {
+		return names
+	}
+	for i := 0; i < len(text); {
+		start := strings.Index(text[i:], l.left)
+		if start < 0 {
+			break
+		}
+		start += i + len(l.left)
+		end := strings.Index(text[start:], l.right)
+		if end < 0 {
+			break
+		}
+		names[strings.TrimSpace(text[start:start+end])] = true
+		i = start + end + len(l.right)
+	}
+	return names
+}
+
+// checkHTML returns the position of the first unbalanced HTML tag, or -1 if
+// they are balanced.
+func checkHTML(text string) (int, string) {
+	type openTag struct {
+		name string
+		pos  int
+	}
+	var stack []openTag
+	for _, m := range htmlRegexp.FindAllStringSubmatchIndex(text, -1) {
+		name := strings.ToLower(text[m[4]:m[5]])
+		closing, selfClosing := m[3] > m[2], m[7] > m[6]
+		switch {
+		case selfClosing || voidTags[name]:
+		case !closing:
+			stack = append(stack, openTag{name, m[0]})
+		case len(stack) == 0 || stack[len(stack)-1].name != name:
+			return m[0], fmt.Sprintf("unmatched closing tag </%s>", name)
+		default:
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if len(stack) != 0 {
+		return stack[len(stack)-1].pos, fmt.Sprintf("unclosed tag <%s>", stack[len(stack)-1].name)
+	}
+	return -1, ""
+}
+
+// requiredForms returns the plural categories used by the language for the
+// integer counts, which are sampled from the CLDR rules.
+func requiredForms(rules *plural.Rules, tag language.Tag) []string {
+	seen := make([]bool, len(pluralForms))
+	for n := 0; n <= 1000; n++ {
+		seen[rules.MatchPlural(tag, n, 0, 0, 0, 0)] = true
+	}
+	seen[rules.MatchPlural(tag, 1000000, 0, 0, 0, 0)] = true
+	var forms []string
+	for i, ok := range seen {
+		if ok {
+			forms = append(forms, pluralForms[i])
+		}
+	}
+	return forms
+}
+
+// hasSelector reports whether the plural or select argument has the option of
+// the selector.
+func hasSelector(n msgNode, selector string) bool {
+	for _, opt := range n.options {
+		if opt.selector == selector {
+			return true
+		}
+	}
+	return false
+}
+
+// isPluralSelector reports whether the selector is a plural category or an
+// exact match like `=0`, or a bare number of the legacy syntax.
+func isPluralSelector(selector string) bool {
+	for _, form := range pluralForms {
+		if selector == form {
+			return true
+		}
+	}
+	_, ok := exactSelector(selector)
+	return ok
+}
+
+// walkMessage calls fn for each node of the message recursively.
+func walkMessage(nodes []msgNode, fn func(n msgNode)) {
+	for _, n := range nodes {
+		fn(n)
+		for _, opt := range n.options {
+			walkMessage(opt.value, fn)
+		}
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
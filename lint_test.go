@@ -0,0 +1,66 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	pkgs := []*Package{
+		{Language: "en", Data: map[string]string{
+			"greet": "Hi {name}, <b>welcome</b>",
+			"items": "{n, plural, one {# item} other {# items}}",
+			"rank":  "{n, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}",
+			"extra": "Only in en",
+		}},
+		{Language: "ru", Data: map[string]string{
+			"greet": "Привет {user}, <b>добро пожаловать",
+			"items": "{n, plural, one {# товар} other {# товаров}}",
+			"rank":  "{n, selectordinal, other {#-й}}",
+		}},
+		{Language: "ja", Data: map[string]string{
+			"greet": "{name}さん、<b>ようこそ</b>",
+			"items": "{n, plural, one {# 個} many {# 個} other {# 個}",
+			"rank":  "{n, select, first {1位}}",
+		}},
+	}
+	report := Lint("en", pkgs)
+	assert.Equal(t, 3, report.Packages)
+	assert.Equal(t, 10, report.Messages)
+	assert.Equal(t, []LintIssue{
+		{Language: "ja", Key: "extra", Rule: LintRuleMissing, Severity: LintWarning, Message: "missing translation", Pos: -1},
+		{Language: "ja", Key: "items", Rule: LintRuleSyntax, Severity: LintError, Message: "invalid ICU format string: unclosed '{' at 0", Pos: 0},
+		{Language: "ja", Key: "rank", Rule: LintRulePluralOther, Severity: LintError, Message: `select "n" misses the other option`, Pos: 0},
+		{Language: "ru", Key: "extra", Rule: LintRuleMissing, Severity: LintWarning, Message: "missing translation", Pos: -1},
+		{Language: "ru", Key: "greet", Rule: LintRuleHTML, Severity: LintError, Message: "unclosed tag <b>", Pos: 21},
+		{Language: "ru", Key: "greet", Rule: LintRulePlaceholder, Severity: LintError, Message: `placeholder "name" is missing`, Pos: -1},
+		{Language: "ru", Key: "greet", Rule: LintRulePlaceholder, Severity: LintError, Message: `placeholder "user" is not in the source message`, Pos: -1},
+		{Language: "ru", Key: "items", Rule: LintRulePluralCategory, Severity: LintWarning, Message: `plural "n" misses the few option required by ru`, Pos: 0},
+		{Language: "ru", Key: "items", Rule: LintRulePluralCategory, Severity: LintWarning, Message: `plural "n" misses the many option required by ru`, Pos: 0},
+	}, report.Issues)
+	assert.Equal(t, 5, report.Errors)
+	assert.Equal(t, 4, report.Warnings)
+}
+
+func TestLintDelimiters(t *testing.T) {
+	pkgs := []*Package{
+		{Language: "en", Data: map[string]string{"a": "[[name]] has {n, plural, one {# apple} other {# apples}}", "b": "[[a]] and [[b]]"}},
+		{Language: "de", Data: map[string]string{"a": "[[name]] hat {n, plural, =0 {keine} one {# Apfel} other {# Äpfel}}", "b": "[[a]] und [[b]] ]]"}},
+		{Language: "fr", Data: map[string]string{"a": "[[nom]] a {n, plural, one {# pomme} other {# pommes}}", "b": "[[a]] et [[b"}},
+	}
+	report := Lint("en", pkgs, WithLeftDelimiter("[["), WithRightDelimiter("]]"))
+	assert.Equal(t, []LintIssue{
+		{Language: "de", Key: "b", Rule: LintRuleDelimiter, Severity: LintError, Message: `unmatched "]]"`, Pos: 16},
+		{Language: "fr", Key: "a", Rule: LintRulePlaceholder, Severity: LintError, Message: `placeholder "name" is missing`, Pos: -1},
+		{Language: "fr", Key: "a", Rule: LintRulePlaceholder, Severity: LintError, Message: `placeholder "nom" is not in the source message`, Pos: -1},
+		{Language: "fr", Key: "b", Rule: LintRuleDelimiter, Severity: LintError, Message: `unclosed "[["`, Pos: 9},
+		{Language: "fr", Key: "b", Rule: LintRulePlaceholder, Severity: LintError, Message: `placeholder "b" is missing`, Pos: -1},
+	}, report.Issues)
+
+	report = Lint("en", []*Package{{Language: "en", Data: map[string]string{"a": "<br/><p>x</i>", "b": "{n, plural, one {#} bad {#} other {#}}"}}})
+	assert.Equal(t, []LintIssue{
+		{Language: "en", Key: "a", Rule: LintRuleHTML, Severity: LintError, Message: "unmatched closing tag </i>", Pos: 9},
+		{Language: "en", Key: "b", Rule: LintRulePluralCategory, Severity: LintError, Message: `plural "n" has an unknown selector "bad"`, Pos: 0},
+	}, report.Issues)
+}
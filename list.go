@@ -0,0 +1,202 @@
+package i18n
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// ListStyle is the CLDR list pattern style to join the items.
+type ListStyle string
+
+const (
+	// ListConjunction joins the items with "and", e.g. "A, B, and C".
+	ListConjunction ListStyle = "conjunction"
+	// ListDisjunction joins the items with "or", e.g. "A, B, or C".
+	ListDisjunction ListStyle = "disjunction"
+	// ListUnit joins the items of a measurement, e.g. "3 feet, 7 inches".
+	ListUnit ListStyle = "unit"
+)
+
+// listPatterns are the CLDR list patterns of a style, where start and middle
+// are "{0}, {1}" if empty.
+type listPatterns struct {
+	two, start, middle, end string
+}
+
+// listLocale is the list data of a language, others is the phrase of the
+// items truncated by the limit, keyed by the plural form.
+type listLocale struct {
+	styles map[ListStyle]listPatterns
+	others map[string]string
+}
+
+// listLocales is the list data of the supported languages, taken from CLDR.
+// The other languages are formatted in English.
+var listLocales = map[string]*listLocale{
+	"en": {
+		styles: map[ListStyle]listPatterns{
+			ListConjunction: {two: "{0} and {1}", end: "{0}, and {1}"},
+			ListDisjunction: {two: "{0} or {1}", end: "{0}, or {1}"},
+			ListUnit:        {two: "{0}, {1}", end: "{0}, {1}"},
+		},
+		others: map[string]string{"one": "{0} other", "other": "{0} others"},
+	},
+	"zh": {
+		styles: map[ListStyle]listPatterns{
+			ListConjunction: {two: "{0}和{1}", start: "{0}、{1}", middle: "{0}、{1}", end: "{0}和{1}"},
+			ListDisjunction: {two: "{0}或{1}", start: "{0}、{1}", middle: "{0}、{1}", end: "{0}或{1}"},
+			ListUnit:        {two: "{0}{1}", start: "{0}{1}", middle: "{0}{1}", end: "{0}{1}"},
+		},
+		others: map[string]string{"other": "其他{0}项"},
+	},
+	"ja": {
+		styles: map[ListStyle]listPatterns{
+			ListConjunction: {two: "{0}、{1}", start: "{0}、{1}", middle: "{0}、{1}", end: "{0}、{1}"},
+			ListDisjunction: {two: "{0}または{1}", start: "{0}、{1}", middle: "{0}、{1}", end: "{0}、または{1}"},
+			ListUnit:        {two: "{0} {1}", start: "{0} {1}", middle: "{0} {1}", end: "{0} {1}"},
+		},
+		others: map[string]string{"other": "他{0}件"},
+	},
+	"ko": {
+		styles: map[ListStyle]listPatterns{
+			ListConjunction: {two: "{0} 및 {1}", end: "{0} 및 {1}"},
+			ListDisjunction: {two: "{0} 또는 {1}", end: "{0} 또는 {1}"},
+			ListUnit:        {two: "{0} {1}", start: "{0} {1}", middle: "{0} {1}", end: "{0} {1}"},
+		},
+		others: map[string]string{"other": "외 {0}개"},
+	},
+	"de": {
+		styles: map[ListStyle]listPatterns{
+			ListConjunction: {two: "{0} und {1}", end: "{0} und {1}"},
+			ListDisjunction: {two: "{0} oder {1}", end: "{0} oder {1}"},
+			ListUnit:        {two: "{0}, {1}", end: "{0} und {1}"},
+		},
+		others: map[string]string{"one": "{0} weiterer", "other": "{0} weitere"},
+	},
+	"fr": {
+		styles: map[ListStyle]listPatterns{
+			ListConjunction: {two: "{0} et {1}", end: "{0} et {1}"},
+			ListDisjunction: {two: "{0} ou {1}", end: "{0} ou {1}"},
+			ListUnit:        {two: "{0} et {1}", end: "{0} et {1}"},
+		},
+		others: map[string]string{"one": "{0} autre", "other": "{0} autres"},
+	},
+	"es": {
+		styles: map[ListStyle]listPatterns{
+			ListConjunction: {two: "{0} y {1}", end: "{0} y {1}"},
+			ListDisjunction: {two: "{0} o {1}", end: "{0} o {1}"},
+			ListUnit:        {two: "{0} y {1}", end: "{0} y {1}"},
+		},
+		others: map[string]string{"other": "{0} más"},
+	},
+	"pt": {
+		styles: map[ListStyle]listPatterns{
+			ListConjunction: {two: "{0} e {1}", end: "{0} e {1}"},
+			ListDisjunction: {two: "{0} ou {1}", end: "{0} ou {1}"},
+			ListUnit:        {two: "{0} e {1}", end: "{0} e {1}"},
+		},
+		others: map[string]string{"other": "mais {0}"},
+	},
+	"ru": {
+		styles: map[ListStyle]listPatterns{
+			ListConjunction: {two: "{0} и {1}", end: "{0} и {1}"},
+			ListDisjunction: {two: "{0} или {1}", end: "{0} или {1}"},
+			ListUnit:        {two: "{0} {1}", start: "{0} {1}", middle: "{0} {1}", end: "{0} {1}"},
+		},
+		others: map[string]string{"other": "ещё {0}"},
+	},
+}
+
+// FormatList joins the items with the CLDR list pattern of the language and
+// style. If limit is positive and there are more items, only the first limit
+// items are listed and the rest are summarized like "3 others".
+func FormatList(lang string, items []string, style ListStyle, limit int) string {
+	base, _ := language.Make(lang).Base()
+	l, ok := listLocales[base.String()]
+	if !ok {
+		l = listLocales["en"]
+	}
+	p, ok := l.styles[style]
+	if !ok {
+		p = l.styles[ListConjunction]
+	}
+	if limit > 0 && len(items) > limit {
+		rest := len(items) - limit
+		others, ok := l.others[pluralForms[plural.Cardinal.MatchPlural(language.Make(lang), rest, 0, 0, 0, 0)]]
+		if !ok {
+			others = l.others["other"]
+		}
+		num, _ := formatNumber(lang, rest, "")
+		items = append(items[:limit:limit], strings.Replace(others, "{0}", num, 1))
+	}
+
+	join := func(pattern, a, b string) string {
+		if len(pattern) == 0 {
+			pattern = "{0}, {1}"
+		}
+		return strings.NewReplacer("{0}", a, "{1}", b).Replace(pattern)
+	}
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	case 2:
+		return join(p.two, items[0], items[1])
+	}
+	// The patterns are applied from the end, e.g. start(A, middle(B, end(C, D))).
+	text := join(p.end, items[len(items)-2], items[len(items)-1])
+	for i := len(items) - 3; i > 0; i-- {
+		text = join(p.middle, items[i], text)
+	}
+	return join(p.start, items[0], text)
+}
+
+// formatList formats the slice argument with the style like `disjunction` and
+// the `limit:N`, both are optional and separated by commas or spaces.
+func formatList(lang string, val interface{}, style string) (string, error) {
+	if !isList(val) {
+		return fmt.Sprintf("%v", val), nil
+	}
+	listStyle, limit := ListConjunction, 0
+	for _, token := range strings.FieldsFunc(style, func(r rune) bool { return r == ',' || r == ' ' }) {
+		switch {
+		case strings.HasPrefix(token, "limit:"):
+			n, err := strconv.Atoi(strings.TrimPrefix(token, "limit:"))
+			if err != nil || n < 1 {
+				return "", fmt.Errorf("%w: invalid list limit %q", ErrInvalidICUFormat, token)
+			}
+			limit = n
+		case token == string(ListConjunction) || token == string(ListDisjunction) || token == string(ListUnit):
+			listStyle = ListStyle(token)
+		default:
+			return "", fmt.Errorf("%w: unknown list style %q", ErrInvalidICUFormat, token)
+		}
+	}
+
+	v := reflect.ValueOf(val)
+	items := make([]string, v.Len())
+	for i := range items {
+		item := v.Index(i).Interface()
+		if isNumber(item) {
+			items[i], _ = formatNumber(lang, item, "")
+		} else {
+			items[i] = fmt.Sprintf("%v", item)
+		}
+	}
+	return FormatList(lang, items, listStyle, limit), nil
+}
+
+// isList reports whether the value is a slice or array other than bytes.
+func isList(val interface{}) bool {
+	if val == nil {
+		return false
+	}
+	t := reflect.TypeOf(val)
+	return (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() != reflect.Uint8
+}
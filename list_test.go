@@ -0,0 +1,69 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatList(t *testing.T) {
+	names := []string{"Alice", "Bob", "Carol", "Dave", "Eve"}
+	for _, item := range []struct {
+		lang   string
+		items  []string
+		style  ListStyle
+		limit  int
+		result string
+	}{
+		{"en", nil, ListConjunction, 0, ""},
+		{"en", names[:1], ListConjunction, 0, "Alice"},
+		{"en", names[:2], ListConjunction, 0, "Alice and Bob"},
+		{"en", names[:4], ListConjunction, 0, "Alice, Bob, Carol, and Dave"},
+		{"en", names[:3], ListDisjunction, 0, "Alice, Bob, or Carol"},
+		{"en", names, ListConjunction, 2, "Alice, Bob, and 3 others"},
+		{"en", names, ListConjunction, 4, "Alice, Bob, Carol, Dave, and 1 other"},
+		{"en", names[:2], ListConjunction, 2, "Alice and Bob"},
+		{"de", names[:3], ListConjunction, 0, "Alice, Bob und Carol"},
+		{"de", names, ListConjunction, 1, "Alice und 4 weitere"},
+		{"zh", names[:3], ListConjunction, 0, "Alice、Bob和Carol"},
+		{"ja", names[:3], ListDisjunction, 0, "Alice、Bob、またはCarol"},
+		{"fr-CA", names[:3], ListDisjunction, 0, "Alice, Bob ou Carol"},
+		{"xx", names[:3], ListUnit, 0, "Alice, Bob, Carol"},
+	} {
+		assert.Equal(t, item.result, FormatList(item.lang, item.items, item.style, item.limit), item)
+	}
+	// The items are not modified by the limit.
+	assert.Equal(t, "Carol", names[2])
+}
+
+func TestListArguments(t *testing.T) {
+	c, err := NewClient(1, 2, WithFetcher(&mockFetcher{}), WithOverrides(NewStaticOverrides(Override{Texts: map[string]string{
+		"invite": "{names, list, limit:2} {names, plural, one {is} other {are}} invited",
+	}})))
+	assert.Nil(t, err)
+	defer c.Shutdown(context.TODO())
+
+	vars := map[string]interface{}{
+		"names": []string{"<Ann>", "Bob", "Cy"}, "sizes": []interface{}{1200, 3.5}, "bytes": []byte("ab"),
+	}
+	res, err := c.processVars("{names} | {sizes, list, unit} | {names, list, disjunction limit:1} | {bytes}", "de", nil, false, vars, "", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "&lt;Ann&gt;, Bob und Cy | 1.200, 3,5 | &lt;Ann&gt; oder 2 weitere | [97 98]", res)
+	_, err = c.processVars("{names, list, limit:0}", "en", nil, false, vars, "", "")
+	assert.True(t, errors.Is(err, ErrInvalidICUFormat))
+	_, err = c.processVars("{names, list, serial}", "en", nil, false, vars, "", "")
+	assert.True(t, errors.Is(err, ErrInvalidICUFormat))
+
+	// Test the length of the list as the plural count.
+	names := []string{"Ann", "Bob", "Cy"}
+	text, err := c.GetText(context.TODO(), "en", "invite", WithPluralCount(names),
+		WithArguments(map[string]interface{}{"names": names}))
+	assert.Nil(t, err)
+	assert.Equal(t, "Ann, Bob, and 1 other are invited", text)
+	text, err = c.GetText(context.TODO(), "en", "key5", WithPluralCount(names[:1]),
+		WithArguments(map[string]interface{}{"farm": "ByteDance"}))
+	assert.Nil(t, err)
+	assert.Equal(t, "I have 1 apple from ByteDance.", text)
+}
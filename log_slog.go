@@ -0,0 +1,73 @@
+//go:build go1.21
+// +build go1.21
+
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// NewSlogLogger creates a structured logger which writes to the given
+// `log/slog` logger, and the fields are converted to the slog attributes.
+func NewSlogLogger(logger *slog.Logger) StructuredLogger {
+	return &slogLogger{logger}
+}
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// Debug implements the `Logger` interface.
+func (l *slogLogger) Debug(format string, v ...interface{}) {
+	l.logf(slog.LevelDebug, format, v...)
+}
+
+// Info implements the `Logger` interface.
+func (l *slogLogger) Info(format string, v ...interface{}) {
+	l.logf(slog.LevelInfo, format, v...)
+}
+
+// Warn implements the `Logger` interface.
+func (l *slogLogger) Warn(format string, v ...interface{}) {
+	l.logf(slog.LevelWarn, format, v...)
+}
+
+// Error implements the `Logger` interface.
+func (l *slogLogger) Error(format string, v ...interface{}) {
+	l.logf(slog.LevelError, format, v...)
+}
+
+// Log implements the `StructuredLogger` interface.
+func (l *slogLogger) Log(level Level, msg string, fields ...Field) {
+	var lvl slog.Level
+	switch level {
+	case LevelDebug:
+		lvl = slog.LevelDebug
+	case LevelInfo:
+		lvl = slog.LevelInfo
+	case LevelWarn:
+		lvl = slog.LevelWarn
+	case LevelError:
+		lvl = slog.LevelError
+	default:
+		return
+	}
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, lvl) {
+		return
+	}
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, f := range fields {
+		attrs = append(attrs, slog.Any(f.Key, f.Value))
+	}
+	l.logger.LogAttrs(ctx, lvl, msg, attrs...)
+}
+
+func (l *slogLogger) logf(level slog.Level, format string, v ...interface{}) {
+	ctx := context.Background()
+	if l.logger.Enabled(ctx, level) {
+		l.logger.Log(ctx, level, fmt.Sprintf(format, v...))
+	}
+}
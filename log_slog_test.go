@@ -0,0 +1,30 @@
+//go:build go1.21
+// +build go1.21
+
+package i18n
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	h := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	l := NewSlogLogger(slog.New(h))
+	l.Debug("test %s", "debug")
+	l.Warn("test %s", "warn")
+	l.Log(LevelError, "fetch failed", Field{"project", 1}, Field{"language", "en"})
+	assert.Equal(t, "level=WARN msg=\"test warn\"\nlevel=ERROR msg=\"fetch failed\" project=1 language=en\n", buf.String())
+}
@@ -0,0 +1,343 @@
+package i18n
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// nodeKind is the kind of a node in the parsed ICU message.
+type nodeKind int
+
+const (
+	nodeText          nodeKind = iota // the literal text
+	nodeArg                           // the simple argument, e.g. `{name}` or `{n, number}`
+	nodePound                         // the `#` in a plural option
+	nodePlural                        // `{n, plural, ...}`
+	nodeSelectOrdinal                 // `{n, selectordinal, ...}`
+	nodeSelect                        // `{gender, select, ...}`
+)
+
+// msgNode is a node of the parsed ICU message.
+type msgNode struct {
+	kind nodeKind
+	pos  int // the byte offset in the message
+	// text is the unescaped literal of a text node.
+	text string
+	// name is the argument name, and typ and style are the format type and
+	// style of a simple argument, e.g. `number` and `percent`.
+	name, typ, style string
+	// offset is the `offset:` of a plural argument.
+	offset  int
+	options []msgOption
+}
+
+// msgOption is an option of the plural or select argument, e.g. `one {...}`.
+type msgOption struct {
+	selector string
+	value    []msgNode
+}
+
+// msgParser parses the ICU message format with the apostrophe quoting.
+type msgParser struct {
+	src string
+	pos int
+}
+
+// parseMessage parses the ICU message into nodes.
+func parseMessage(src string) ([]msgNode, error) {
+	p := &msgParser{src: src}
+	nodes, err := p.parseNodes(false, false)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(src) {
+		return nil, p.errorf("unexpected %q", src[p.pos])
+	}
+	return nodes, nil
+}
+
+func (p *msgParser) errorf(format string, v ...interface{}) error {
+	return &FormatError{Pos: p.pos, Msg: fmt.Sprintf(format, v...)}
+}
+
+// parseNodes parses the nodes until the end or the closing brace of a nested
+// message, where the `#` is a pound node in a plural option.
+func (p *msgParser) parseNodes(nested, plural bool) ([]msgNode, error) {
+	var nodes []msgNode
+	var text strings.Builder
+	start := p.pos
+	flush := func() {
+		if text.Len() != 0 {
+			nodes = append(nodes, msgNode{kind: nodeText, pos: start, text: text.String()})
+			text.Reset()
+		}
+	}
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '\'':
+			if text.Len() == 0 {
+				start = p.pos
+			}
+			p.parseQuoted(&text, plural)
+		case c == '{':
+			flush()
+			node, err := p.parseArg(plural)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node)
+		case c == '}':
+			if !nested {
+				return nil, p.errorf("unmatched '}'")
+			}
+			flush()
+			return nodes, nil
+		case c == '#' && plural:
+			flush()
+			nodes = append(nodes, msgNode{kind: nodePound, pos: p.pos})
+			p.pos++
+		default:
+			if text.Len() == 0 {
+				start = p.pos
+			}
+			text.WriteByte(c)
+			p.pos++
+		}
+	}
+	if nested {
+		return nil, p.errorf("unclosed '{'")
+	}
+	flush()
+	return nodes, nil
+}
+
+// parseQuoted parses the apostrophe at the current position, where a doubled
+// apostrophe is a literal one, an apostrophe before a special character starts
+// a quoted literal until the next single apostrophe, and it is literal
+// otherwise.
+func (p *msgParser) parseQuoted(b *strings.Builder, plural bool) {
+	p.pos++
+	if p.pos >= len(p.src) {
+		b.WriteByte('\'')
+		return
+	}
+	switch c := p.src[p.pos]; {
+	case c == '\'':
+		b.WriteByte('\'')
+		p.pos++
+	case c == '{' || c == '}' || (c == '#' && plural):
+		for p.pos < len(p.src) {
+			if p.src[p.pos] == '\'' {
+				if p.pos+1 < len(p.src) && p.src[p.pos+1] == '\'' {
+					b.WriteByte('\'')
+					p.pos += 2
+					continue
+				}
+				p.pos++
+				return
+			}
+			b.WriteByte(p.src[p.pos])
+			p.pos++
+		}
+	default:
+		b.WriteByte('\'')
+	}
+}
+
+// parseArg parses an argument starting with `{`, where plural reports whether
+// it is nested in a plural option.
+func (p *msgParser) parseArg(plural bool) (msgNode, error) {
+	node := msgNode{kind: nodeArg, pos: p.pos}
+	p.pos++
+	node.name = p.parseWord()
+	if len(node.name) == 0 {
+		return node, p.errorf("missing argument name")
+	}
+	if p.consume('}') {
+		return node, nil
+	}
+	if !p.consume(',') {
+		return node, p.errorf("expected ',' or '}'")
+	}
+	node.typ = p.parseWord()
+	switch node.typ {
+	case "plural", "selectordinal", "select":
+		if !p.consume(',') {
+			return node, p.errorf("expected ',' after %s", node.typ)
+		}
+		return p.parseOptions(node, plural)
+	case "":
+		return node, p.errorf("missing argument type")
+	}
+	if p.consume(',') {
+		end := strings.IndexByte(p.src[p.pos:], '}')
+		if end < 0 {
+			return node, p.errorf("unclosed '{'")
+		}
+		node.style = strings.TrimSpace(p.src[p.pos : p.pos+end])
+		p.pos += end
+	}
+	if !p.consume('}') {
+		return node, p.errorf("expected '}'")
+	}
+	return node, nil
+}
+
+// parseOptions parses the options of a plural or select argument, the `#` in
+// a select option nested in a plural option is still a pound node.
+func (p *msgParser) parseOptions(node msgNode, plural bool) (msgNode, error) {
+	switch node.typ {
+	case "plural":
+		node.kind = nodePlural
+	case "selectordinal":
+		node.kind = nodeSelectOrdinal
+	default:
+		node.kind = nodeSelect
+	}
+	p.skipSpace()
+	if node.kind != nodeSelect && strings.HasPrefix(p.src[p.pos:], "offset:") {
+		p.pos += len("offset:")
+		p.skipSpace()
+		start := p.pos
+		for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+			p.pos++
+		}
+		offset, err := strconv.Atoi(p.src[start:p.pos])
+		if err != nil {
+			p.pos = start
+			return node, p.errorf("invalid offset")
+		}
+		node.offset = offset
+	}
+	for {
+		if p.consume('}') {
+			break
+		}
+		if len(node.options) != 0 {
+			// The legacy syntax separates the options by commas.
+			p.consume(',')
+		}
+		if p.pos >= len(p.src) {
+			p.pos = node.pos
+			return node, p.errorf("unclosed '{'")
+		}
+		pos := p.pos
+		selector := p.parseWord()
+		if len(selector) == 0 {
+			return node, p.errorf("missing selector")
+		}
+		if !p.consume('{') {
+			return node, p.errorf("expected '{' after selector %q", selector)
+		}
+		value, err := p.parseNodes(true, plural || node.kind != nodeSelect)
+		if err != nil {
+			return node, err
+		}
+		p.pos++ // the closing brace
+		for _, opt := range node.options {
+			if opt.selector == selector {
+				p.pos = pos
+				return node, p.errorf("duplicate selector %q", selector)
+			}
+		}
+		node.options = append(node.options, msgOption{selector: selector, value: value})
+	}
+	if len(node.options) == 0 {
+		return node, p.errorf("missing options of %s", node.typ)
+	}
+	return node, nil
+}
+
+// parseWord parses a name, type or selector surrounded by spaces.
+func (p *msgParser) parseWord() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == '{' || c == '}' || c == ',' || c == '\'' || c == '#' || isSpace(c) {
+			break
+		}
+		p.pos++
+	}
+	word := p.src[start:p.pos]
+	p.skipSpace()
+	return word
+}
+
+func (p *msgParser) consume(c byte) bool {
+	p.skipSpace()
+	if p.pos < len(p.src) && p.src[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *msgParser) skipSpace() {
+	for p.pos < len(p.src) && isSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// writeMessage writes the nodes back into the ICU message format, the
+// apostrophes are only doubled where needed.
+func writeMessage(b *strings.Builder, nodes []msgNode, plural bool) {
+	for _, n := range nodes {
+		switch n.kind {
+		case nodeText:
+			writeText(b, n.text, plural)
+		case nodePound:
+			b.WriteByte('#')
+		case nodeArg:
+			b.WriteString("{" + n.name)
+			if len(n.typ) != 0 {
+				b.WriteString(", " + n.typ)
+			}
+			if len(n.style) != 0 {
+				b.WriteString(", " + n.style)
+			}
+			b.WriteByte('}')
+		default:
+			b.WriteString("{" + n.name + ", " + n.typ + ",")
+			if n.offset != 0 {
+				b.WriteString(" offset:" + strconv.Itoa(n.offset))
+			}
+			for _, opt := range n.options {
+				b.WriteString(" " + opt.selector + " {")
+				writeMessage(b, opt.value, plural || n.kind != nodeSelect)
+				b.WriteByte('}')
+			}
+			b.WriteByte('}')
+		}
+	}
+}
+
+func writeText(b *strings.Builder, text string, plural bool) {
+	special := func(c byte) bool {
+		return c == '{' || c == '}' || (c == '#' && plural)
+	}
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case special(c):
+			b.WriteByte('\'')
+			j := i
+			for j < len(text) && special(text[j]) {
+				j++
+			}
+			b.WriteString(text[i:j])
+			b.WriteByte('\'')
+			i = j - 1
+		case c == '\'' && (i+1 == len(text) || text[i+1] == '\'' || special(text[i+1])):
+			b.WriteString("''")
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package i18n
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMessage(t *testing.T) {
+	tests := []struct {
+		src, expected string
+	}{
+		{"plain text", "plain text"},
+		{"Hi {name}, {n, number, percent}", "Hi {name}, {n, number, percent}"},
+		{"{ n , plural , offset:1 =0 {none} one {# item} other {# items}}", "{n, plural, offset:1 =0 {none} one {# item} other {# items}}"},
+		{"{g, select, male {He has {n, plural, one {#} other {#s}}} other {They}}", "{g, select, male {He has {n, plural, one {#} other {#s}}} other {They}}"},
+		{"{n, plural, other {{g, select, other {# '#'}}}}", "{n, plural, other {{g, select, other {# '#'}}}}"},
+		{"It's '{literal}' and ''quoted''", "It's '{'literal'}' and 'quoted''"},
+		{"# is plain outside plural", "# is plain outside plural"},
+	}
+	for _, tt := range tests {
+		nodes, err := parseMessage(tt.src)
+		assert.Nil(t, err, tt.src)
+		var b strings.Builder
+		writeMessage(&b, nodes, false)
+		assert.Equal(t, tt.expected, b.String())
+	}
+
+	nodes, err := parseMessage("It's '{literal}'")
+	assert.Nil(t, err)
+	assert.Equal(t, []msgNode{{kind: nodeText, text: "It's {literal}"}}, nodes)
+
+	for src, pos := range map[string]string{
+		"{name":                          "at 5",
+		"text }":                         "at 5",
+		"{n, plural, one {#}":            "at 0",
+		"{n, plural, one {a} one {b}}":   "at 20",
+		"{n, plural, offset:x other {}}": "at 19",
+		"{, select, other {}}":           "at 1",
+	} {
+		_, err := parseMessage(src)
+		assert.True(t, errors.Is(err, ErrInvalidICUFormat), src)
+		assert.True(t, strings.HasSuffix(err.Error(), pos), err.Error())
+	}
+}
@@ -0,0 +1,323 @@
+package i18n
+
+import (
+	"bytes"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ExtendedMetricer extends the `Metricer` with the timers, histograms and
+// gauges. The SDK emits these kinds of metrics only if the metricer given by
+// `WithMetricer` implements this interface, otherwise they are emitted as
+// counters for compatibility.
+type ExtendedMetricer interface {
+	Metricer
+
+	// EmitTimer records a duration sample with the given name and tags.
+	EmitTimer(name string, d time.Duration, tags map[string]string)
+
+	// EmitHistogram records a value sample into the distribution of the given
+	// name and tags.
+	EmitHistogram(name string, value float64, tags map[string]string)
+
+	// EmitGauge sets the current value of the given name and tags.
+	EmitGauge(name string, value float64, tags map[string]string)
+}
+
+// emitTimer records the duration with the metricer, which falls back to a
+// counter in milliseconds if the metricer is not an `ExtendedMetricer`.
+func emitTimer(m Metricer, name string, d time.Duration, tags map[string]string) {
+	if m == nil {
+		return
+	}
+	if em, ok := m.(ExtendedMetricer); ok {
+		em.EmitTimer(name, d, tags)
+		return
+	}
+	m.EmitCounter(name, d.Milliseconds(), tags)
+}
+
+// emitHistogram records the value with the metricer, which falls back to a
+// counter if the metricer is not an `ExtendedMetricer`.
+func emitHistogram(m Metricer, name string, value float64, tags map[string]string) {
+	if m == nil {
+		return
+	}
+	if em, ok := m.(ExtendedMetricer); ok {
+		em.EmitHistogram(name, value, tags)
+		return
+	}
+	m.EmitCounter(name, value, tags)
+}
+
+// emitGauge sets the value with the metricer, which falls back to a counter
+// if the metricer is not an `ExtendedMetricer`.
+func emitGauge(m Metricer, name string, value float64, tags map[string]string) {
+	if m == nil {
+		return
+	}
+	if em, ok := m.(ExtendedMetricer); ok {
+		em.EmitGauge(name, value, tags)
+		return
+	}
+	m.EmitCounter(name, value, tags)
+}
+
+var (
+	// DefaultTimerBuckets are the upper bounds in second of the timer buckets.
+	DefaultTimerBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+	// DefaultHistogramBuckets are the upper bounds of the histogram buckets,
+	// which fit the package sizes in number of keys.
+	DefaultHistogramBuckets = []float64{10, 50, 100, 500, 1000, 5000, 10000, 50000}
+)
+
+const (
+	metricTypeCounter   = "counter"
+	metricTypeGauge     = "gauge"
+	metricTypeHistogram = "histogram"
+)
+
+// PrometheusMetricer is an in-process metrics registry which implements the
+// `ExtendedMetricer` interface and renders the metrics in the Prometheus text
+// exposition format as an `http.Handler`. The metric names are prefixed with
+// `starling_` and the dots are replaced with underscores, e.g. the timer
+// `proxy.http.latency` is exposed as `starling_proxy_http_latency_seconds`.
+type PrometheusMetricer struct {
+	mu      sync.Mutex
+	metrics map[string]*promMetric
+}
+
+type promMetric struct {
+	typ     string
+	buckets []float64
+	series  map[string]*promSeries
+}
+
+type promSeries struct {
+	labels string
+	value  float64
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewPrometheusMetricer creates an empty in-process metrics registry.
+func NewPrometheusMetricer() *PrometheusMetricer {
+	return &PrometheusMetricer{metrics: make(map[string]*promMetric)}
+}
+
+// EmitCounter implements the `Metricer` interface.
+func (p *PrometheusMetricer) EmitCounter(name string, value interface{}, tags map[string]string) {
+	v, ok := toFloat(value)
+	if !ok {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if s := p.series(promName(name)+"_total", metricTypeCounter, nil, tags); s != nil {
+		s.value += v
+	}
+}
+
+// EmitTimer implements the `ExtendedMetricer` interface.
+func (p *PrometheusMetricer) EmitTimer(name string, d time.Duration, tags map[string]string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if s := p.series(promName(name)+"_seconds", metricTypeHistogram, DefaultTimerBuckets, tags); s != nil {
+		s.observe(DefaultTimerBuckets, d.Seconds())
+	}
+}
+
+// EmitHistogram implements the `ExtendedMetricer` interface.
+func (p *PrometheusMetricer) EmitHistogram(name string, value float64, tags map[string]string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if s := p.series(promName(name), metricTypeHistogram, DefaultHistogramBuckets, tags); s != nil {
+		s.observe(DefaultHistogramBuckets, value)
+	}
+}
+
+// EmitGauge implements the `ExtendedMetricer` interface.
+func (p *PrometheusMetricer) EmitGauge(name string, value float64, tags map[string]string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if s := p.series(promName(name), metricTypeGauge, nil, tags); s != nil {
+		s.value = value
+	}
+}
+
+// ServeHTTP implements the `http.Handler` interface to expose the metrics.
+func (p *PrometheusMetricer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write(p.Render())
+}
+
+// Render returns all the metrics in the Prometheus text exposition format.
+func (p *PrometheusMetricer) Render() []byte {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	names := make([]string, 0, len(p.metrics))
+	for name := range p.metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b bytes.Buffer
+	for _, name := range names {
+		m := p.metrics[name]
+		b.WriteString("# TYPE " + name + " " + m.typ + "\n")
+		keys := make([]string, 0, len(m.series))
+		for k := range m.series {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			s := m.series[k]
+			if m.typ != metricTypeHistogram {
+				b.WriteString(name + wrapLabels(s.labels) + " " + formatFloat(s.value) + "\n")
+				continue
+			}
+			var cumulative uint64
+			for i, le := range m.buckets {
+				cumulative += s.counts[i]
+				b.WriteString(name + "_bucket" + wrapLabels(joinLabels(s.labels, `le="`+formatFloat(le)+`"`)) +
+					" " + strconv.FormatUint(cumulative, 10) + "\n")
+			}
+			b.WriteString(name + "_bucket" + wrapLabels(joinLabels(s.labels, `le="+Inf"`)) +
+				" " + strconv.FormatUint(s.count, 10) + "\n")
+			b.WriteString(name + "_sum" + wrapLabels(s.labels) + " " + formatFloat(s.sum) + "\n")
+			b.WriteString(name + "_count" + wrapLabels(s.labels) + " " + strconv.FormatUint(s.count, 10) + "\n")
+		}
+	}
+	return b.Bytes()
+}
+
+// series returns the series of the given metric and tags, it returns nil if
+// the metric name is registered with another type.
+func (p *PrometheusMetricer) series(name, typ string, buckets []float64, tags map[string]string) *promSeries {
+	m, ok := p.metrics[name]
+	if !ok {
+		m = &promMetric{typ: typ, buckets: buckets, series: make(map[string]*promSeries)}
+		p.metrics[name] = m
+	}
+	if m.typ != typ {
+		return nil
+	}
+	labels := promLabels(tags)
+	s, ok := m.series[labels]
+	if !ok {
+		s = &promSeries{labels: labels}
+		if typ == metricTypeHistogram {
+			s.counts = make([]uint64, len(buckets))
+		}
+		m.series[labels] = s
+	}
+	return s
+}
+
+func (s *promSeries) observe(buckets []float64, v float64) {
+	for i, le := range buckets {
+		if v <= le {
+			s.counts[i]++
+			break
+		}
+	}
+	s.count++
+	s.sum += v
+}
+
+// promName converts the metric name to a valid Prometheus metric name.
+func promName(name string) string {
+	return "starling_" + sanitizePromName(name)
+}
+
+func sanitizePromName(name string) string {
+	b := []byte(name)
+	for i, c := range b {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_') {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+// promLabels formats the tags into the sorted Prometheus labels.
+func promLabels(tags map[string]string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, sanitizePromName(k)+`="`+escapeLabelValue(tags[k])+`"`)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func escapeLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+func joinLabels(labels, extra string) string {
+	if len(labels) == 0 {
+		return extra
+	}
+	return labels + "," + extra
+}
+
+func wrapLabels(labels string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	return "{" + labels + "}"
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// toFloat converts the numeric value to float64.
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case time.Duration:
+		return v.Seconds(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,61 @@
+package i18n
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusMetricer(t *testing.T) {
+	p := NewPrometheusMetricer()
+	tags := map[string]string{"status": "success"}
+	p.EmitCounter(httpProxyMetricsKeyThroughput, 1, tags)
+	p.EmitCounter(httpProxyMetricsKeyThroughput, int64(2), tags)
+	p.EmitCounter(httpProxyMetricsKeyThroughput, "invalid", tags)
+	p.EmitTimer(httpProxyMetricsKeyLatency, 20*time.Millisecond, tags)
+	p.EmitGauge(clientCacheSizeMetricsKey, 3, nil)
+	p.EmitGauge(clientCacheSizeMetricsKey, 5, nil)
+	p.EmitHistogram(clientPackageSizeMetricsKey, 120, map[string]string{"language": `e"n`})
+	assert.Equal(t, map[string]string{"status": "success"}, tags)
+
+	srv := httptest.NewServer(p)
+	defer srv.Close()
+	resp, err := srv.Client().Get(srv.URL)
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	text := string(body)
+	t.Log(text)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/plain")
+	assert.Contains(t, text, "# TYPE starling_proxy_http_throughput_total counter\n"+
+		`starling_proxy_http_throughput_total{status="success"} 3`)
+	assert.Contains(t, text, "# TYPE starling_client_cache_size gauge\nstarling_client_cache_size 5\n")
+	assert.Contains(t, text, `starling_proxy_http_latency_seconds_bucket{status="success",le="0.025"} 1`)
+	assert.Contains(t, text, `starling_proxy_http_latency_seconds_bucket{status="success",le="0.01"} 0`)
+	assert.Contains(t, text, `starling_proxy_http_latency_seconds_count{status="success"} 1`)
+	assert.Contains(t, text, `starling_client_package_size_bucket{language="e\"n",le="500"} 1`)
+	assert.Contains(t, text, `starling_client_package_size_sum{language="e\"n"} 120`)
+}
+
+func TestClientMetrics(t *testing.T) {
+	p := NewPrometheusMetricer()
+	c, err := NewClient(1, 2, WithFetcher(&mockFetcher{}), WithMetricer(p))
+	assert.NotNil(t, c)
+	assert.Nil(t, err)
+	defer c.Shutdown(context.TODO())
+
+	for i := 0; i < 3; i++ {
+		_, err = c.GetPackage(context.TODO(), "en")
+		assert.Nil(t, err)
+	}
+	text := string(p.Render())
+	labels := `{env="normal",language="en",namespaceID="2",projectID="1"}`
+	assert.True(t, strings.Contains(text, "starling_client_cache_miss_total"+labels+" 1"), text)
+	assert.True(t, strings.Contains(text, "starling_client_cache_hit_total"+labels+" 2"), text)
+	assert.True(t, strings.Contains(text, "starling_client_package_size_count"+labels+" 1"), text)
+}
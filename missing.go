@@ -0,0 +1,22 @@
+package i18n
+
+import (
+	"context"
+)
+
+// MissingKeyHandler returns the replacement of a missing key with the lookup
+// context in the result, e.g. the key, the text of the source language or a
+// marker. The replacement is returned as it is, and `ErrKeyNotExist` is
+// returned if ok is false.
+type MissingKeyHandler func(ctx context.Context, res TextResult) (text string, ok bool)
+
+// MissingKeyAsKey replaces the missing key with the key itself.
+func MissingKeyAsKey(ctx context.Context, res TextResult) (string, bool) {
+	return res.Key, true
+}
+
+// MissingKeyMarker replaces the missing key with the key in the brackets like
+// `⟦key⟧`, which makes the missing texts stand out in development.
+func MissingKeyMarker(ctx context.Context, res TextResult) (string, bool) {
+	return "\u27e6" + res.Key + "\u27e7", true
+}
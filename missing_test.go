@@ -0,0 +1,62 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMissingKey(t *testing.T) {
+	c, err := NewClient(1, 2, WithFetcher(&mockFetcher{}), WithMissingKeyHandler(MissingKeyMarker))
+	assert.Nil(t, err)
+	defer c.Shutdown(context.TODO())
+
+	text, err := c.GetText(context.TODO(), "en", "not-exist-key")
+	assert.Nil(t, err)
+	assert.Equal(t, "⟦not-exist-key⟧", text)
+	// The replacement is never formatted.
+	text, err = c.GetText(context.TODO(), "en", "not-exist-key", WithPluralCount(2))
+	assert.Nil(t, err)
+	assert.Equal(t, "⟦not-exist-key⟧", text)
+	text, err = c.GetText(context.TODO(), "en", "key1")
+	assert.Nil(t, err)
+	assert.Equal(t, "v1", text)
+
+	// The default message of the request takes precedence over the handler.
+	res, err := c.ExplainText(context.TODO(), "en", "cart", WithPluralCount(2),
+		WithDefaultMessage("{n, plural, one {# item} other {# items}} in {name}'s cart"),
+		WithArguments(map[string]interface{}{"name": "Eve"}))
+	assert.Nil(t, err)
+	assert.Equal(t, &TextResult{
+		Text:           "2 items in Eve's cart",
+		Key:            "cart",
+		Language:       "en",
+		ProjectID:      1,
+		NamespaceID:    2,
+		Env:            EnvNormal,
+		Version:        "12",
+		ReleaseVersion: "1.2",
+		Source:         SourceDefault,
+	}, res)
+
+	var got TextResult
+	texts, err := c.GetTexts(context.TODO(), "en", []string{"key1", "a", "b"}, WithMissingKeyHandler(
+		func(ctx context.Context, res TextResult) (string, bool) {
+			got = res
+			return "", res.Key == "a"
+		}))
+	assert.Equal(t, map[string]string{"key1": "v1", "a": ""}, texts)
+	assert.True(t, errors.Is(err.(*BatchError).Errors["b"], ErrKeyNotExist))
+	assert.Equal(t, TextResult{Key: "b", Language: "en", ProjectID: 1, NamespaceID: 2, Env: EnvNormal,
+		Version: "12", ReleaseVersion: "1.2"}, got)
+
+	rich, err := c.GetRichText(context.TODO(), "en", "terms", []string{"b"}, WithMissingKeyHandler(MissingKeyAsKey))
+	assert.Nil(t, err)
+	assert.Equal(t, RichText{{Text: "terms"}}, rich)
+	rich, err = c.GetRichText(context.TODO(), "en", "terms", []string{"b"}, WithDefaultMessage("<b>{n}</b> terms"),
+		WithArguments(map[string]interface{}{"n": 3}))
+	assert.Nil(t, err)
+	assert.Equal(t, "*3* terms", rich.Render(map[string]TagHandler{"b": func(s string) string { return "*" + s + "*" }}))
+}
@@ -1,6 +1,8 @@
 package i18n
 
 import (
+	"context"
+	"strconv"
 	"sync"
 	"time"
 )
@@ -34,6 +36,19 @@ type option struct {
 	arguments            map[string]interface{}
 	leftDelimiter        string
 	rightDelimiter       string
+	interceptors         []Interceptor
+	tracer               Tracer
+	preload              []PreloadTarget
+	shutdownHooks        []func(ctx context.Context) error
+	batchConcurrency     int
+	namespaceStack       []int64
+	override             OverrideSource
+	pseudo               *PseudoConfig
+	timeZone             *time.Location
+	bidiIsolation        bool
+	defaultMessage       *string
+	missingKeyHandler    MissingKeyHandler
+	owner                *handle // the registry handle which sends the request
 }
 
 // WithAppKey sets app key of the project for authorization.
@@ -141,8 +156,11 @@ func WithRetryPolicy(policy RetryPolicy) Option {
 	}
 }
 
-// WithLogger sets the logger to output the internal state content.
+// WithLogger sets the logger to output the internal state content, the auth
+// tokens, app keys and operators are redacted from the content.
 func WithLogger(logger Logger) Option {
+	// The redaction layer is created once here rather than per request.
+	logger = withRedaction(logger)
 	return func(o *option) {
 		o.logger = logger
 	}
@@ -213,6 +231,174 @@ func WithRightDelimiter(val string) Option {
 	}
 }
 
+// WithInterceptors sets the interceptors to wrap the outbound http requests,
+// which are called in the given order.
+func WithInterceptors(val ...Interceptor) Option {
+	return func(o *option) {
+		o.interceptors = val
+	}
+}
+
+// WithTracer sets the tracer to record the spans of fetching, cache lookup and
+// formatting, and propagate the trace context to the outbound requests.
+func WithTracer(tracer Tracer) Option {
+	return func(o *option) {
+		o.tracer = tracer
+	}
+}
+
+// WithPreload sets the packages to load in background when creating the
+// client, which are used to check the readiness of the client.
+func WithPreload(targets ...PreloadTarget) Option {
+	return func(o *option) {
+		o.preload = targets
+	}
+}
+
+// WithShutdownHooks sets the functions to call when shutting down the client,
+// such as flushing the snapshot of the packages.
+func WithShutdownHooks(hooks ...func(ctx context.Context) error) Option {
+	return func(o *option) {
+		o.shutdownHooks = hooks
+	}
+}
+
+// WithBatchConcurrency sets the maximum number of packages to fetch
+// concurrently in the batch APIs, default is 4.
+func WithBatchConcurrency(n int) Option {
+	return func(o *option) {
+		o.batchConcurrency = n
+	}
+}
+
+// WithNamespaceStack sets an ordered list of namespaces to look up the texts,
+// the first namespace containing the key wins. It allows a base namespace to
+// be shared and overridden by the more specific ones, e.g.
+// `WithNamespaceStack(productNS, sharedNS)`. A namespace failed to fetch is
+// skipped unless all of them failed.
+func WithNamespaceStack(nids ...int64) Option {
+	return func(o *option) {
+		o.namespaceStack = nids
+	}
+}
+
+// WithOverrides sets the source of the local texts which take precedence over
+// the fetched packages, see `NewStaticOverrides` and `NewFileOverrides`.
+func WithOverrides(src OverrideSource) Option {
+	return func(o *option) {
+		o.override = src
+	}
+}
+
+// WithPseudoLocale enables the pseudo locales `PseudoAccented` and `PseudoBidi`,
+// whose packages are synthesized from the package of the base language for the
+// UI testing instead of being fetched.
+func WithPseudoLocale(cfg PseudoConfig) Option {
+	return func(o *option) {
+		o.pseudo = &cfg
+	}
+}
+
+// WithTimeZone sets the location to format the `time.Time` arguments in, the
+// location of the time itself is used by default.
+func WithTimeZone(loc *time.Location) Option {
+	return func(o *option) {
+		o.timeZone = loc
+	}
+}
+
+// WithBidiIsolation wraps the interpolated arguments and plural counts in the
+// Unicode directional isolates for the RTL languages, so that the LTR values
+// like the usernames and numbers are not scrambled in the surrounding RTL text.
+func WithBidiIsolation(enable bool) Option {
+	return func(o *option) {
+		o.bidiIsolation = enable
+	}
+}
+
+// WithDefaultMessage sets the message used if the key is missing, which is
+// formatted with the arguments and plurals as the found one.
+func WithDefaultMessage(msg string) Option {
+	return func(o *option) {
+		o.defaultMessage = &msg
+	}
+}
+
+// WithMissingKeyHandler sets the handler called if the key is missing and no
+// default message is given, e.g. `MissingKeyMarker` in development.
+func WithMissingKeyHandler(h MissingKeyHandler) Option {
+	return func(o *option) {
+		o.missingKeyHandler = h
+	}
+}
+
+// mergeOptions collapses the options into a single one which sets the merged
+// state, so that adding options at runtime never grows the options applied to
+// each request. It replaces the whole option, so the result must be applied
+// first, e.g. as the global options of a client.
+func mergeOptions(old, opts []Option) []Option {
+	merged := &option{}
+	for _, f := range old {
+		f(merged)
+	}
+	for _, f := range opts {
+		f(merged)
+	}
+	return []Option{func(o *option) {
+		*o = *merged
+	}}
+}
+
+// peekOptions applies the global and request options to a temporary option and
+// calls fn with it, which picks the options needed before handling a request.
+func peekOptions(global, opts []Option, fn func(o *option)) {
+	o := op.get()
+	defer op.put(o)
+	for _, f := range global {
+		f(o)
+	}
+	for _, f := range opts {
+		f(o)
+	}
+	fn(o)
+}
+
+// fields returns the structured log fields which identify the request.
+// cacheKey returns the key of the cached package of the request. The packages
+// loaded by a registry handle are cached apart from the other handles, so that
+// they are never served without the credentials of the handle.
+func (o *option) cacheKey(lang string) string {
+	key := buildCacheKey(o.projectID, o.namespaceID, o.env, lang)
+	if o.owner != nil {
+		key += "@" + registryKey(o.owner.projectID, o.owner.namespaceID)
+	}
+	return key
+}
+
+func (o *option) fields(extra ...Field) []Field {
+	ver := o.version
+	if len(ver) == 0 {
+		ver = "latest"
+	}
+	return append([]Field{
+		{"project", o.projectID},
+		{"namespace", o.namespaceID},
+		{"env", o.env},
+		{"language", o.language},
+		{"version", ver},
+	}, extra...)
+}
+
+// tags returns the metrics tags which identify the requested package.
+func (o *option) tags() map[string]string {
+	return map[string]string{
+		"projectID":   strconv.FormatInt(o.projectID, 10),
+		"namespaceID": strconv.FormatInt(o.namespaceID, 10),
+		"env":         o.env,
+		"language":    o.language,
+	}
+}
+
 // optionPool manages the option objects based on `sync.Pool` for reuse.
 type optionPool struct {
 	sync.Pool
@@ -252,6 +438,19 @@ func (p *optionPool) put(obj *option) {
 		obj.arguments = nil
 		obj.leftDelimiter = ""
 		obj.rightDelimiter = ""
+		obj.interceptors = nil
+		obj.tracer = nil
+		obj.preload = nil
+		obj.shutdownHooks = nil
+		obj.batchConcurrency = 0
+		obj.namespaceStack = nil
+		obj.override = nil
+		obj.pseudo = nil
+		obj.timeZone = nil
+		obj.bidiIsolation = false
+		obj.defaultMessage = nil
+		obj.missingKeyHandler = nil
+		obj.owner = nil
 	}
 	p.Pool.Put(obj)
 }
@@ -31,7 +31,7 @@ func TestOption(t *testing.T) {
 		{WithOnlyVersion(true), option{onlyVersion: true}},
 		{WithOperator("operator"), option{operator: "operator"}},
 		{WithRetryPolicy(retry), option{retryPolicy: retry}},
-		{WithLogger(logger), option{logger: logger}},
+		{WithLogger(logger), option{logger: withRedaction(logger)}},
 		{WithMetricer(metricer), option{metricer: metricer}},
 		{WithFetcher(fetcher), option{fetcher: fetcher}},
 		{WithRefreshInterval(time.Second), option{refreshInterval: time.Second}},
@@ -0,0 +1,242 @@
+package i18n
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const defaultOverridePollInterval = 5 * time.Second
+
+// OverrideSource provides the local texts which take precedence over the
+// fetched packages, e.g. to hotfix a wrong translation before the release or to
+// tweak the texts in development without a platform account.
+type OverrideSource interface {
+	// Overrides returns the override texts of the given project, namespace,
+	// env and language. The returned map must not be modified.
+	Overrides(pid, nid int64, env, lang string) map[string]string
+}
+
+// Override is a set of override texts. The zero value of the project,
+// namespace, env and language matches any of them.
+type Override struct {
+	ProjectID   int64             `json:"project_id"`
+	NamespaceID int64             `json:"namespace_id"`
+	Env         string            `json:"env"`
+	Language    string            `json:"language"`
+	Texts       map[string]string `json:"texts"`
+}
+
+func (v *Override) match(pid, nid int64, env, lang string) bool {
+	return (v.ProjectID == 0 || v.ProjectID == pid) &&
+		(v.NamespaceID == 0 || v.NamespaceID == nid) &&
+		(len(v.Env) == 0 || v.Env == env) &&
+		(len(v.Language) == 0 || v.Language == lang)
+}
+
+// staticOverrides merges the matched overrides in order, the latter wins.
+type staticOverrides struct {
+	overrides []Override
+	merged    sync.Map // cache key -> map[string]string
+}
+
+// NewStaticOverrides creates an override source of the given overrides, the
+// latter ones overwrite the former ones of the same key.
+func NewStaticOverrides(overrides ...Override) OverrideSource {
+	return &staticOverrides{overrides: overrides}
+}
+
+// Overrides implements the `OverrideSource` interface's method.
+func (s *staticOverrides) Overrides(pid, nid int64, env, lang string) map[string]string {
+	key := buildCacheKey(pid, nid, env, lang)
+	if v, ok := s.merged.Load(key); ok {
+		return v.(map[string]string)
+	}
+	var texts map[string]string
+	for i := range s.overrides {
+		if !s.overrides[i].match(pid, nid, env, lang) {
+			continue
+		}
+		if texts == nil {
+			texts = make(map[string]string, len(s.overrides[i].Texts))
+		}
+		for k, v := range s.overrides[i].Texts {
+			texts[k] = v
+		}
+	}
+	s.merged.Store(key, texts)
+	return texts
+}
+
+// fileOverrides loads the overrides from a JSON file or the JSON files of a
+// directory, and reloads them when the files are changed.
+type fileOverrides struct {
+	path     string
+	interval time.Duration
+	logger   Logger
+	source   atomic.Value // *staticOverrides
+	stamp    string       // the modification of the files, guarded by checking
+	checked  int64        // unix nano of the last check, accessed atomically
+	checking int32
+}
+
+// NewFileOverrides creates an override source from the given path, which is a
+// JSON file of an `Override` list or a directory of such files loaded in the
+// name order. The files are polled for changes at the interval set by
+// `WithRefreshInterval`, default is 5 seconds. The logger set by `WithLogger`
+// records the reloading.
+func NewFileOverrides(path string, opts ...Option) (OverrideSource, error) {
+	o := &option{}
+	for _, f := range opts {
+		f(o)
+	}
+	f := &fileOverrides{
+		path:     path,
+		interval: o.refreshInterval,
+		logger:   withRedaction(o.logger),
+		checked:  time.Now().UnixNano(),
+	}
+	if f.interval <= 0 {
+		f.interval = defaultOverridePollInterval
+	}
+	stamp, err := f.stat()
+	if err != nil {
+		return nil, err
+	}
+	source, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	f.stamp = stamp
+	f.source.Store(source)
+	return f, nil
+}
+
+// Overrides implements the `OverrideSource` interface's method.
+func (f *fileOverrides) Overrides(pid, nid int64, env, lang string) map[string]string {
+	f.poll(time.Now())
+	return f.source.Load().(*staticOverrides).Overrides(pid, nid, env, lang)
+}
+
+// poll reloads the overrides if the files are changed since the last check.
+// Only one caller checks at a time and the others use the current overrides.
+func (f *fileOverrides) poll(now time.Time) {
+	if now.UnixNano()-atomic.LoadInt64(&f.checked) < int64(f.interval) ||
+		!atomic.CompareAndSwapInt32(&f.checking, 0, 1) {
+		return
+	}
+	defer atomic.StoreInt32(&f.checking, 0)
+	atomic.StoreInt64(&f.checked, now.UnixNano())
+
+	stamp, err := f.stat()
+	if err != nil {
+		logWith(f.logger, LevelWarn, "starling: stat overrides failed", Field{"path", f.path}, Field{"error", err})
+		return
+	}
+	if stamp == f.stamp {
+		return
+	}
+	source, err := f.load()
+	if err != nil {
+		logWith(f.logger, LevelWarn, "starling: reload overrides failed", Field{"path", f.path}, Field{"error", err})
+		return
+	}
+	f.stamp = stamp
+	f.source.Store(source)
+	logWith(f.logger, LevelInfo, "starling: overrides reloaded", Field{"path", f.path}, Field{"count", len(source.overrides)})
+}
+
+// files returns the JSON files of the path in the name order.
+func (f *fileOverrides) files() ([]string, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{f.path}, nil
+	}
+	files, err := filepath.Glob(filepath.Join(f.path, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// stat returns a stamp of the names, sizes and modification times of the files.
+func (f *fileOverrides) stat() (string, error) {
+	files, err := f.files()
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, name := range files {
+		info, err := os.Stat(name)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(name + "#" + strconv.FormatInt(info.ModTime().UnixNano(), 10) +
+			"#" + strconv.FormatInt(info.Size(), 10) + ";")
+	}
+	return b.String(), nil
+}
+
+func (f *fileOverrides) load() (*staticOverrides, error) {
+	files, err := f.files()
+	if err != nil {
+		return nil, err
+	}
+	var overrides []Override
+	for _, name := range files {
+		data, err := ioutil.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		var list []Override
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, err
+		}
+		overrides = append(overrides, list...)
+	}
+	return &staticOverrides{overrides: overrides}, nil
+}
+
+// applyOverrides writes the override texts into the copy of the fetched package.
+func (c *client) applyOverrides(o *option, pkg *Package) *Package {
+	if o.override == nil {
+		return pkg
+	}
+	texts := o.override.Overrides(o.projectID, o.namespaceID, o.env, o.language)
+	if len(texts) == 0 {
+		return pkg
+	}
+	if pkg.Data == nil {
+		pkg.Data = make(map[string]string, len(texts))
+	}
+	for k, v := range texts {
+		pkg.Data[k] = v
+	}
+	c.overridden(o, o.namespaceID, o.language, "")
+	return pkg
+}
+
+// overridden records the overridden text or package, so that the overrides do
+// not linger unnoticed. The key is only logged, which is unbounded as a label
+// of the metrics.
+func (c *client) overridden(o *option, nid int64, lang, key string) {
+	logWith(o.logger, LevelDebug, "starling: text overridden",
+		Field{"project", o.projectID}, Field{"namespace", nid}, Field{"env", o.env},
+		Field{"language", lang}, Field{"key", key})
+	o.metricer.EmitCounter(clientOverrideHitMetricsKey, 1, map[string]string{
+		"projectID":   strconv.FormatInt(o.projectID, 10),
+		"namespaceID": strconv.FormatInt(nid, 10),
+		"env":         o.env,
+		"language":    lang,
+	})
+}
@@ -0,0 +1,127 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticOverrides(t *testing.T) {
+	f := &layerFetcher{data: map[int64]map[string]string{
+		2: {"title": "Product", "ok": "OK"},
+		3: {"title": "Shared", "bye": "Bye"},
+	}}
+	src := NewStaticOverrides(
+		Override{Language: "en", Texts: map[string]string{"title": "Any", "new": "New {name}"}},
+		Override{ProjectID: 1, NamespaceID: 3, Language: "en", Texts: map[string]string{"bye": "See you"}},
+		Override{ProjectID: 1, NamespaceID: 2, Env: EnvTest, Texts: map[string]string{"ok": "Fine"}},
+	)
+	assert.Equal(t, map[string]string{"title": "Any", "new": "New {name}", "bye": "See you"}, src.Overrides(1, 3, EnvNormal, "en"))
+	assert.Nil(t, src.Overrides(1, 2, EnvNormal, "ja"))
+
+	c, err := NewClient(1, 2, WithFetcher(f), WithOverrides(src))
+	assert.Nil(t, err)
+	defer c.Shutdown(context.TODO())
+
+	text, err := c.GetText(context.TODO(), "en", "new", WithArguments(map[string]interface{}{"name": "Bob"}))
+	assert.Nil(t, err)
+	assert.Equal(t, "New Bob", text)
+	res, err := c.ExplainText(context.TODO(), "en", "ok")
+	assert.Nil(t, err)
+	assert.Equal(t, SourceRemote, res.Source)
+	res, err = c.ExplainText(context.TODO(), "en", "ok", WithEnv(EnvTest))
+	assert.Nil(t, err)
+	assert.Equal(t, "Fine", res.Text)
+	assert.Equal(t, SourceOverride, res.Source)
+	assert.Equal(t, "12", res.Version)
+
+	// Test the overrides of each layer take precedence over its package only.
+	res, err = c.ExplainText(context.TODO(), "ja", "bye", WithNamespaceStack(2, 3))
+	assert.Nil(t, err)
+	assert.Equal(t, &TextResult{Text: "Bye", Key: "bye", Language: "ja", ProjectID: 1, NamespaceID: 3,
+		Env: EnvNormal, Version: "13", ReleaseVersion: "1.3", Layer: 1, Source: SourceRemote}, res)
+	texts, err := c.GetTexts(context.TODO(), "en", []string{"title", "bye"}, WithNamespaceStack(2, 3))
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"title": "Any", "bye": "See you"}, texts)
+
+	// Test the overrides are applied to the copies of the packages.
+	pkg, err := c.GetPackage(context.TODO(), "en")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"title": "Any", "ok": "OK", "new": "New {name}"}, pkg.Data)
+	view, err := c.GetPackageView(context.TODO(), "en")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, view.Len())
+
+	// Test falling back on the overrides if the package failed to fetch.
+	text, err = c.GetText(context.TODO(), "en", "title", WithNamespaceID(9))
+	assert.Nil(t, err)
+	assert.Equal(t, "Any", text)
+	_, err = c.GetText(context.TODO(), "en", "ok", WithNamespaceID(9))
+	assert.True(t, errors.Is(err, ErrBackToSourceFailed))
+	texts, err = c.GetTexts(context.TODO(), "en", []string{"title", "ok"}, WithNamespaceID(9))
+	assert.Equal(t, map[string]string{"title": "Any"}, texts)
+	assert.True(t, errors.Is(err.(*BatchError).Errors["ok"], ErrBackToSourceFailed))
+}
+
+// tagMetricer records the tags of the counters.
+type tagMetricer struct {
+	mu   sync.Mutex
+	tags map[string][]map[string]string
+}
+
+func (m *tagMetricer) EmitCounter(name string, value interface{}, tags map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tags[name] = append(m.tags[name], tags)
+}
+
+func TestOverrideMetrics(t *testing.T) {
+	m := &tagMetricer{tags: make(map[string][]map[string]string)}
+	c, err := NewClient(1, 2, WithFetcher(&mockFetcher{}), WithMetricer(m),
+		WithOverrides(NewStaticOverrides(Override{Texts: map[string]string{"key1": "v0"}})))
+	assert.Nil(t, err)
+	defer c.Shutdown(context.TODO())
+
+	text, err := c.GetText(context.TODO(), "en", "key1")
+	assert.Nil(t, err)
+	assert.Equal(t, "v0", text)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// The keys are never the labels of the metrics.
+	assert.Equal(t, []map[string]string{{"projectID": "1", "namespaceID": "2", "env": EnvNormal, "language": "en"}},
+		m.tags[clientOverrideHitMetricsKey])
+}
+
+func TestFileOverrides(t *testing.T) {
+	dir, err := ioutil.TempDir("", "overrides")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = NewFileOverrides(filepath.Join(dir, "none.json"))
+	assert.NotNil(t, err)
+	write := func(name, content string) {
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	write("a.json", `[{"language": "en", "texts": {"title": "A", "ok": "OK"}}]`)
+	write("b.json", `[{"project_id": 1, "texts": {"title": "B"}}]`)
+
+	src, err := NewFileOverrides(dir, WithRefreshInterval(time.Millisecond))
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"title": "B", "ok": "OK"}, src.Overrides(1, 2, EnvNormal, "en"))
+	assert.Equal(t, map[string]string{"title": "A", "ok": "OK"}, src.Overrides(3, 2, EnvNormal, "en"))
+
+	// Test reloading the changed files and keeping the overrides on failure.
+	write("b.json", `[{"project_id": 1, "texts": {"title": "C", "new": "New"}}]`)
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, map[string]string{"title": "C", "ok": "OK", "new": "New"}, src.Overrides(1, 2, EnvNormal, "en"))
+	write("c.json", `invalid`)
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, map[string]string{"title": "C", "ok": "OK", "new": "New"}, src.Overrides(1, 2, EnvNormal, "en"))
+}
@@ -0,0 +1,145 @@
+package i18n
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// Clone returns a deep copy of the package which can be modified freely.
+func (p *Package) Clone() *Package {
+	if p == nil {
+		return nil
+	}
+	cp := *p
+	if p.Data != nil {
+		cp.Data = make(map[string]string, len(p.Data))
+		for k, v := range p.Data {
+			cp.Data[k] = v
+		}
+	}
+	return &cp
+}
+
+// PackageView is a read-only view of a cached package which avoids copying
+// the whole package data. The zero value is an empty view.
+type PackageView struct {
+	pkg *Package
+}
+
+// Version returns the timestamp version of the package.
+func (v PackageView) Version() string {
+	if v.pkg == nil {
+		return ""
+	}
+	return v.pkg.Version
+}
+
+// ReleaseVersion returns the release version of the package.
+func (v PackageView) ReleaseVersion() string {
+	if v.pkg == nil {
+		return ""
+	}
+	return v.pkg.ReleaseVersion
+}
+
+// Language returns the language code of the package.
+func (v PackageView) Language() string {
+	if v.pkg == nil {
+		return ""
+	}
+	return v.pkg.Language
+}
+
+// Get returns the text of the given key and whether it exists.
+func (v PackageView) Get(key string) (string, bool) {
+	if v.pkg == nil {
+		return "", false
+	}
+	text, ok := v.pkg.Data[key]
+	return text, ok
+}
+
+// Len returns the number of texts in the package.
+func (v PackageView) Len() int {
+	if v.pkg == nil {
+		return 0
+	}
+	return len(v.pkg.Data)
+}
+
+// Keys returns the sorted keys of the texts in the package.
+func (v PackageView) Keys() []string {
+	if v.pkg == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(v.pkg.Data))
+	for k := range v.pkg.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Range calls fn for each text in the package until fn returns false.
+func (v PackageView) Range(fn func(key, text string) bool) {
+	if v.pkg == nil {
+		return
+	}
+	for k, text := range v.pkg.Data {
+		if !fn(k, text) {
+			return
+		}
+	}
+}
+
+// Clone returns a deep copy of the viewed package.
+func (v PackageView) Clone() *Package {
+	return v.pkg.Clone()
+}
+
+// cacheEntry holds an immutable package snapshot in the local cache along with
+// the states used by the background refresher, including the credentials of
+// the request which may differ among the projects of a `Registry`. The package
+// is replaced as a whole on refreshing and is never modified in place.
+type cacheEntry struct {
+	atime       int64 // unix nano of the last access, accessed atomically
+	pkg         atomic.Value
+	projectID   int64
+	namespaceID int64
+	env         string
+	language    string
+	appKey      string
+	operator    string
+	owner       *handle // the registry handle which loads it first
+}
+
+func newCacheEntry(pkg *Package, o *option) *cacheEntry {
+	e := &cacheEntry{
+		atime:       time.Now().UnixNano(),
+		projectID:   o.projectID,
+		namespaceID: o.namespaceID,
+		env:         o.env,
+		language:    o.language,
+		appKey:      o.appKey,
+		operator:    o.operator,
+		owner:       o.owner,
+	}
+	e.pkg.Store(pkg)
+	return e
+}
+
+// load returns the current package snapshot.
+func (e *cacheEntry) load() *Package {
+	return e.pkg.Load().(*Package)
+}
+
+// touch records the access time of the entry.
+func (e *cacheEntry) touch(now time.Time) {
+	atomic.StoreInt64(&e.atime, now.UnixNano())
+}
+
+// expired reports whether the entry is not accessed in the given duration.
+func (e *cacheEntry) expired(now time.Time, d time.Duration) bool {
+	return time.Unix(0, atomic.LoadInt64(&e.atime)).Add(d).Before(now)
+}
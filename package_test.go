@@ -0,0 +1,109 @@
+package i18n
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageClone(t *testing.T) {
+	var nilPkg *Package
+	assert.Nil(t, nilPkg.Clone())
+
+	p := &Package{Version: "1", Data: map[string]string{"k": "v"}}
+	cp := p.Clone()
+	assert.Equal(t, p, cp)
+	cp.Data["k"] = "changed"
+	assert.Equal(t, "v", p.Data["k"])
+}
+
+func TestPackageView(t *testing.T) {
+	var empty PackageView
+	assert.Equal(t, 0, empty.Len())
+	assert.Nil(t, empty.Keys())
+	_, ok := empty.Get("k")
+	assert.False(t, ok)
+
+	v := PackageView{&Package{Version: "1", ReleaseVersion: "1.0", Language: "en", Data: map[string]string{"b": "2", "a": "1"}}}
+	assert.Equal(t, "1", v.Version())
+	assert.Equal(t, "1.0", v.ReleaseVersion())
+	assert.Equal(t, "en", v.Language())
+	assert.Equal(t, 2, v.Len())
+	assert.Equal(t, []string{"a", "b"}, v.Keys())
+	text, ok := v.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "1", text)
+	var count int
+	v.Range(func(key, text string) bool {
+		count++
+		return false
+	})
+	assert.Equal(t, 1, count)
+}
+
+func TestCacheEntry(t *testing.T) {
+	e := newCacheEntry(&Package{Language: "en"}, &option{projectID: 1, namespaceID: 2, env: EnvNormal, language: "en-US"})
+	assert.Equal(t, "en", e.load().Language)
+	assert.Equal(t, "en-US", e.language)
+
+	now := time.Now()
+	e.touch(now)
+	assert.False(t, e.expired(now.Add(time.Minute), time.Hour))
+	assert.True(t, e.expired(now.Add(2*time.Hour), time.Hour))
+}
+
+// TestClientPackageRace should be run with `go test -race` to detect the data
+// races among the readers, the refresher and the callers modifying packages.
+func TestClientPackageRace(t *testing.T) {
+	c, err := NewClient(1, 2, WithFetcher(&mockFetcher{}))
+	assert.NotNil(t, c)
+	assert.Nil(t, err)
+	defer c.Shutdown(context.TODO())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(4)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				pkg, err := c.GetPackage(context.TODO(), "en")
+				assert.Nil(t, err)
+				pkg.Data["key1"] = "modified"
+				delete(pkg.Data, "key2")
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				text, err := c.GetText(context.TODO(), "en", "key1")
+				assert.Nil(t, err)
+				assert.Equal(t, "v1", text)
+				view, err := c.GetPackageView(context.TODO(), "en")
+				assert.Nil(t, err)
+				_, ok := view.Get("key2")
+				assert.True(t, ok)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				c.refresh(context.TODO())
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				_, err := c.GetPackage(context.TODO(), "de", WithOnlyVersion(true))
+				assert.Nil(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	text, err := c.GetText(context.TODO(), "de", "key1")
+	assert.Nil(t, err)
+	assert.Equal(t, "v1", text)
+}
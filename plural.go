@@ -0,0 +1,136 @@
+package i18n
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/number"
+)
+
+// choosePlural returns the option of the plural argument matching the count and the
+// count minus the offset. The exact matches like `=1` are checked before the
+// categories like `one`, which are matched with the count minus the offset.
+func (r *messageRenderer) choosePlural(n msgNode, count interface{}) ([]msgNode, string, error) {
+	num, err := pluralNumber(count)
+	if err != nil {
+		return nil, "", err
+	}
+	val, _ := strconv.ParseFloat(num, 64)
+	for _, opt := range n.options {
+		if exact, ok := exactSelector(opt.selector); ok && exact == val {
+			return opt.value, offsetNumber(num, val, n.offset), nil
+		}
+	}
+
+	num = offsetNumber(num, val, n.offset)
+	rules := plural.Cardinal
+	if n.kind == nodeSelectOrdinal {
+		rules = plural.Ordinal
+	}
+	i, v, w, f, t := pluralOperands(num)
+	form := pluralForms[rules.MatchPlural(r.rules, i, v, w, f, t)]
+	var other []msgNode
+	for _, opt := range n.options {
+		switch opt.selector {
+		case form:
+			return opt.value, num, nil
+		case "other":
+			other = opt.value
+		}
+	}
+	if other == nil {
+		return nil, "", &FormatError{Pos: n.pos, Msg: fmt.Sprintf("%s %q misses the other option", n.typ, n.name)}
+	}
+	return other, num, nil
+}
+
+// formatCount formats the decimal count according to the language, keeping its
+// visible fraction digits.
+func formatCount(lang, num string) string {
+	val, _ := strconv.ParseFloat(num, 64)
+	_, v, _, _, _ := pluralOperands(num)
+	return printer(lang).Sprint(number.Decimal(val, number.MinFractionDigits(v), number.MaxFractionDigits(v)))
+}
+
+// exactSelector parses the exact match selector like `=1`, the bare numbers
+// of the legacy syntax are exact matches as well.
+func exactSelector(selector string) (float64, bool) {
+	selector = strings.TrimPrefix(selector, "=")
+	for i, c := range selector {
+		if (c < '0' || c > '9') && c != '.' && (c != '-' || i != 0) {
+			return 0, false
+		}
+	}
+	val, err := strconv.ParseFloat(selector, 64)
+	return val, err == nil
+}
+
+// pluralNumber returns the decimal string of the plural count, which keeps
+// the visible fraction digits of a string count like "1.50". The count of a
+// list is its length.
+func pluralNumber(count interface{}) (string, error) {
+	if isList(count) {
+		return strconv.Itoa(reflect.ValueOf(count).Len()), nil
+	}
+	switch v := count.(type) {
+	case string:
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return "", fmt.Errorf("%w: invalid plural count %q", ErrInvalidICUFormat, v)
+		}
+		return strings.TrimSpace(v), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	}
+	if !isNumber(count) {
+		return "", fmt.Errorf("%w: invalid plural count %v", ErrInvalidICUFormat, count)
+	}
+	return fmt.Sprintf("%d", count), nil
+}
+
+// offsetNumber subtracts the offset from the decimal number, keeping the
+// fraction digits.
+func offsetNumber(num string, val float64, offset int) string {
+	if offset == 0 {
+		return num
+	}
+	digits := 0
+	if dot := strings.IndexByte(num, '.'); dot >= 0 {
+		digits = len(num) - dot - 1
+	}
+	return strconv.FormatFloat(val-float64(offset), 'f', digits, 64)
+}
+
+// pluralOperands returns the CLDR plural operands of the decimal number: the
+// integer digits i, the number of visible fraction digits v and w without the
+// trailing zeros, and the visible fraction digits f and t without the trailing
+// zeros.
+func pluralOperands(num string) (i, v, w, f, t int) {
+	num = strings.TrimLeft(num, "+-")
+	intPart, frac := num, ""
+	if dot := strings.IndexByte(num, '.'); dot >= 0 {
+		intPart, frac = num[:dot], num[dot+1:]
+	}
+	// The integer which overflows only keeps its last digits, which are
+	// enough for the modulo rules.
+	if len(intPart) > 18 {
+		intPart = "1" + intPart[len(intPart)-17:]
+	}
+	i, _ = strconv.Atoi(intPart)
+	v, f = len(frac), atoi(frac)
+	frac = strings.TrimRight(frac, "0")
+	w, t = len(frac), atoi(frac)
+	return
+}
+
+func atoi(s string) int {
+	if len(s) > 9 {
+		s = s[:9]
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
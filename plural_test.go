@@ -0,0 +1,117 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessPluralRules(t *testing.T) {
+	c, err := NewClient(1, 2, WithFetcher(&mockFetcher{}))
+	assert.Nil(t, err)
+	defer c.Shutdown(context.TODO())
+
+	rank := "{n, selectordinal, one {#st} two {#nd} few {#rd} other {#th}} place"
+	items := "{n, plural, =0 {no items} =1 {a single item} one {# item} other {# items}}"
+	for _, item := range []struct {
+		raw, lang, defLang string
+		count              interface{}
+		result             string
+	}{
+		{rank, "en", "", 1, "1st place"},
+		{rank, "en", "", 2, "2nd place"},
+		{rank, "en", "", 23, "23rd place"},
+		{rank, "en", "", 11, "11th place"},
+		{rank, "en", "", 112, "112th place"},
+		{"{n, selectordinal, one {le #er} other {le #e}}", "fr", "", 1, "le 1er"},
+		{"{n, selectordinal, one {le #er} other {le #e}}", "fr", "", 2, "le 2e"},
+		{"{n, selectordinal, many {l''#º} other {il #º}}", "it", "", 8, "l'8º"},
+		{"{n, selectordinal, many {l''#º} other {il #º}}", "it", "", 2, "il 2º"},
+		{items, "en", "", 0, "no items"},
+		{items, "en", "", 1, "a single item"},
+		{items, "en", "", 21, "21 items"},
+		{"{n, plural, one {# item} other {# items}}", "en", "", "1.0", "1.0 items"},
+		{"{n, plural, one {# item} other {# items}}", "en", "", 1.5, "1.5 items"},
+		{"{n, plural, one {# товар} few {# товара} many {# товаров} other {# товара}}", "ru", "", 21, "21 товар"},
+		{"{n, plural, one {# товар} few {# товара} many {# товаров} other {# товара}}", "ru", "", 24, "24 товара"},
+		{"{n, plural, one {# товар} few {# товара} many {# товаров} other {# товара}}", "ru", "", 11, "11 товаров"},
+		{"{n, plural, one {# товар} few {# товара} many {# товаров} other {# товара}}", "ru", "", 1.5, "1,5 товара"},
+		{"{n, plural, one {# Apfel} other {# Äpfel}}", "de", "", 12345, "12.345 Äpfel"},
+		{"{n, plural, one {# livre} other {# livres}}", "fr", "", 0, "0 livre"},
+		{"{n, plural, one {# livre} other {# livres}}", "en-XA", "fr", 0, "0 livre"},
+		{"{n, plural, offset:1 =0 {nobody} =1 {{host}} one {{host} and # other} other {{host} and # others}}", "en", "", 1, "{host}"},
+		{"{n, plural, offset:1 =0 {nobody} =1 {{host}} one {{host} and # other} other {{host} and # others}}", "en", "", 2, "{host} and 1 other"},
+		{"{n, plural, offset:1 =0 {nobody} =1 {{host}} one {{host} and # other} other {{host} and # others}}", "en", "", 1235, "{host} and 1,234 others"},
+		{"#1: {n, plural, one {# '#'tag} other {# '#'tags}}, {n, selectordinal, one {#st} other {#th}}", "en", "", 1, "#1: 1 #tag, 1st"},
+		{"{n, plural, 0 {none} one {# item} other {# items}}", "en", "", 0, "none"},
+	} {
+		res, err := c.processPlural(item.raw, item.lang, item.defLang, item.count, false, nil)
+		assert.Nil(t, err, item.raw)
+		assert.Equal(t, item.result, res, item)
+	}
+
+	_, err = c.processPlural("{n, plural, one {# item}}", "en", "", 2, false, nil)
+	assert.True(t, errors.Is(err, ErrInvalidICUFormat))
+	_, err = c.processPlural("{n, plural, one {# item} other {# items}}", "en", "", "many", false, nil)
+	assert.True(t, errors.Is(err, ErrInvalidICUFormat))
+}
+
+func TestPluralArguments(t *testing.T) {
+	plain := map[string]string{"fine": "It''s fine", "open": "Use '{' to open"}
+	c, err := NewClient(1, 2, WithFetcher(&mockFetcher{}), WithOverrides(NewStaticOverrides(Override{Texts: map[string]string{
+		"fine":    plain["fine"],
+		"open":    plain["open"],
+		"cart":    "#1 seller: {items, plural, one {# item} other {# items}} in {carts, plural, one {# cart} other {# carts}}",
+		"hashtag": "{n, plural, one {# post tagged '#'go} other {# posts tagged '#'go}} #trending",
+		"legacy":  "I have {num, plural, one {# apple}, other {{num} apples}} to store.",
+		"quoted":  "{n, plural, one {# item} other {# items}} use '{'name'}' syntax",
+		"syntax":  "use '{'name'}' syntax for {name}",
+	}})))
+	assert.Nil(t, err)
+	defer c.Shutdown(context.TODO())
+	ctx := context.TODO()
+
+	text, err := c.GetText(ctx, "de", "cart", WithArguments(map[string]interface{}{"items": 1500, "carts": 1}))
+	assert.Nil(t, err)
+	assert.Equal(t, "#1 seller: 1.500 items in 1 cart", text)
+	// The plural count is used for the plurals without an argument.
+	text, err = c.GetText(ctx, "en", "cart", WithPluralCount(2), WithArguments(map[string]interface{}{"items": 1}))
+	assert.Nil(t, err)
+	assert.Equal(t, "#1 seller: 1 item in 2 carts", text)
+	text, err = c.GetText(ctx, "en", "hashtag", WithPluralCount(3))
+	assert.Nil(t, err)
+	assert.Equal(t, "3 posts tagged #go #trending", text)
+	text, err = c.GetText(ctx, "en", "legacy", WithArguments(map[string]interface{}{"num": 1234}))
+	assert.Nil(t, err)
+	assert.Equal(t, "I have 1,234 apples to store.", text)
+
+	// The quoted braces are literals whether or not a plural or an argument is given.
+	args := WithArguments(map[string]interface{}{"name": "X"})
+	text, err = c.GetText(ctx, "en", "quoted", WithPluralCount(2), args)
+	assert.Nil(t, err)
+	assert.Equal(t, "2 items use {name} syntax", text)
+	text, err = c.GetText(ctx, "en", "syntax", args)
+	assert.Nil(t, err)
+	assert.Equal(t, "use {name} syntax for X", text)
+	// The plain texts without any plural or argument are returned as they are.
+	text, err = c.GetText(ctx, "en", "syntax")
+	assert.Nil(t, err)
+	assert.Equal(t, "use '{'name'}' syntax for {name}", text)
+	for _, key := range []string{"fine", "open"} {
+		text, err = c.GetText(ctx, "en", key)
+		assert.Nil(t, err)
+		assert.Equal(t, plain[key], text)
+		rich, err := c.GetRichText(ctx, "en", key, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, RichText{{Text: plain[key]}}, rich)
+	}
+	rich, err := c.GetRichText(ctx, "en", "quoted", nil, WithPluralCount(1), args)
+	assert.Nil(t, err)
+	assert.Equal(t, RichText{{Text: "1 item use {name} syntax"}}, rich)
+
+	_, err = c.GetText(ctx, "en", "cart", WithArguments(map[string]interface{}{"items": 1}))
+	assert.True(t, errors.Is(err, ErrMissingArgument))
+	assert.Contains(t, err.Error(), `"carts"`)
+}
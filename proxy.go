@@ -4,7 +4,6 @@ import (
 	"compress/gzip"
 	"context"
 	"errors"
-	"fmt"
 	"io"
 	"net"
 	"net/http"
@@ -25,19 +24,19 @@ type Fetcher interface {
 	FetchVersion(ctx context.Context, projectID, namespaceID int64, lang string, opts ...Option) (int64, string, error)
 }
 
-// Package is the data structure which is parsed from the returned value.
+// Package is the data structure which is parsed from the returned value. The
+// packages in the local cache are shared and immutable snapshots.
 type Package struct {
 	Version        string            `json:"version"`
 	ReleaseVersion string            `json:"release_version"`
 	Data           map[string]string `json:"data"`
 	Language       string            `json:"language"`
-
-	atime       *time.Time `json:"-"`
-	projectID   int64      `json:"-"`
-	namespaceID int64      `json:"-"`
-	env         string     `json:"-"`
 }
 
+// errNilResponse is returned if the interceptors return neither a response nor
+// an error.
+var errNilResponse = errors.New("interceptor returned nil response without error")
+
 type httpFetcher struct {
 	httpClient *http.Client
 	option     *option
@@ -56,6 +55,7 @@ func NewHttpFetcher(opts ...Option) *httpFetcher {
 	if o.httpTimeout <= 0 {
 		o.httpTimeout = 10
 	}
+	o.logger = withRedaction(o.logger, o.appKey, o.operator)
 
 	timeout := time.Second * time.Duration(o.httpTimeout)
 	client := &http.Client{
@@ -83,72 +83,7 @@ func (h *httpFetcher) Fetch(ctx context.Context, pid, nid int64, lang string, op
 		f(opt)
 	}
 
-	// Send http request with retry, record the metrics and parse the response.
-	doRequest := func(req *http.Request) (*http.Response, error) {
-		begin := time.Now()
-		resp, err := h.httpClient.Do(req)
-		if h.option.metricer != nil {
-			tag := map[string]string{"status": "success"}
-			if err != nil {
-				tag["status"] = "failed"
-			}
-			elapsed := time.Now().Sub(begin)
-			h.option.metricer.EmitCounter(httpProxyMetricsKeyThroughput, 1, tag)
-			h.option.metricer.EmitCounter(httpProxyMetricsKeyLatency, elapsed.Milliseconds(), tag)
-		}
-		return resp, err
-	}
-
-	// Perform requesting with retry policy.
-	var req *http.Request
-	var resp *http.Response
-	retryTimes := 0
-	retry := h.option.retryPolicy
-	if retry == nil {
-		retry = opt.retryPolicy
-	}
-	for {
-		// Build HTTP request with the given params from primary storage.
-		req, err = h.buildHTTPRequest(pid, nid, lang, false, false, opt)
-		if err != nil {
-			return
-		}
-		resp, err = doRequest(req)
-		if h.option.logger != nil {
-			h.option.logger.Debug("do http request: req=%v, resp=%v, err=%v", req, resp, err)
-		}
-		if err == nil {
-			break
-		}
-
-		// Build HTTP request with the given params from backup storage if not disabled.
-		if !opt.disableBackupStorage {
-			req, err = h.buildHTTPRequest(pid, nid, lang, true, false, opt)
-			if err != nil {
-				return
-			}
-			resp, err = doRequest(req)
-			if h.option.logger != nil {
-				h.option.logger.Debug("do http request: req=%v, resp=%v, err=%v", req, resp, err)
-			}
-			if err == nil {
-				break
-			}
-		}
-
-		retryTimes++
-		if retry != nil && retry.ShouldRetry(retryTimes, err) {
-			time.Sleep(retry.RetryDelay(retryTimes))
-			if h.option.logger != nil {
-				h.option.logger.Info("retry request %d times for err=%v", retryTimes, err)
-			}
-			if resp != nil && resp.Body != nil {
-				resp.Body.Close()
-			}
-			continue
-		}
-		break
-	}
+	resp, err := h.do(ctx, pid, nid, lang, false, opt)
 	if err != nil {
 		return nil, err
 	}
@@ -173,25 +108,31 @@ func (h *httpFetcher) FetchVersion(ctx context.Context, pid, nid int64, lang str
 		f(opt)
 	}
 
-	// Send http request with retry, record the metrics and parse the response.
-	doRequest := func(req *http.Request) (*http.Response, error) {
-		begin := time.Now()
-		resp, err := h.httpClient.Do(req)
-		if h.option.metricer != nil {
-			tag := map[string]string{"status": "success"}
-			if err != nil {
-				tag["status"] = "failed"
-			}
-			elapsed := time.Now().Sub(begin)
-			h.option.metricer.EmitCounter(httpProxyMetricsKeyThroughput, 1, tag)
-			h.option.metricer.EmitCounter(httpProxyMetricsKeyLatency, elapsed.Milliseconds(), tag)
-		}
-		return resp, err
+	resp, err := h.do(ctx, pid, nid, lang, true, opt)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	var result struct {
+		Status int    `json:"status"`
+		Data   string `json:"data"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return
 	}
+	if result.Status != 0 {
+		err = errors.New(result.Data)
+		return
+	}
+	ver, _ = strconv.ParseInt(opt.version, 10, 64)
+	rel = result.Data
+	return
+}
 
-	// Perform requesting with retry policy.
+// do sends the request to the primary storage and then the backup storage
+// if not disabled, and repeats the procedure with the retry policy.
+func (h *httpFetcher) do(ctx context.Context, pid, nid int64, lang string, onlyVersion bool, opt *option) (resp *http.Response, err error) {
 	var req *http.Request
-	var resp *http.Response
 	retryTimes := 0
 	retry := h.option.retryPolicy
 	if retry == nil {
@@ -199,28 +140,22 @@ func (h *httpFetcher) FetchVersion(ctx context.Context, pid, nid int64, lang str
 	}
 	for {
 		// Build HTTP request with the given params from primary storage.
-		req, err = h.buildHTTPRequest(pid, nid, lang, false, true, opt)
+		req, err = h.buildHTTPRequest(ctx, pid, nid, lang, false, onlyVersion, opt)
 		if err != nil {
 			return
 		}
-		resp, err = doRequest(req)
-		if h.option.logger != nil {
-			h.option.logger.Debug("do http request: req=%v, resp=%v, err=%v", req, resp, err)
-		}
+		resp, err = h.roundTrip(req, opt, retryTimes+1, false)
 		if err == nil {
 			break
 		}
 
 		// Build HTTP request with the given params from backup storage if not disabled.
 		if !opt.disableBackupStorage {
-			req, err = h.buildHTTPRequest(pid, nid, lang, true, true, opt)
+			req, err = h.buildHTTPRequest(ctx, pid, nid, lang, true, onlyVersion, opt)
 			if err != nil {
 				return
 			}
-			resp, err = doRequest(req)
-			if h.option.logger != nil {
-				h.option.logger.Debug("do http request: req=%v, resp=%v, err=%v", req, resp, err)
-			}
+			resp, err = h.roundTrip(req, opt, retryTimes+1, true)
 			if err == nil {
 				break
 			}
@@ -228,10 +163,17 @@ func (h *httpFetcher) FetchVersion(ctx context.Context, pid, nid int64, lang str
 
 		retryTimes++
 		if retry != nil && retry.ShouldRetry(retryTimes, err) {
-			time.Sleep(retry.RetryDelay(retryTimes))
-			if h.option.logger != nil {
-				h.option.logger.Info("retry request %d times for err=%v", retryTimes, err)
+			select {
+			case <-time.After(retry.RetryDelay(retryTimes)):
+			case <-ctx.Done():
+				if resp != nil && resp.Body != nil {
+					resp.Body.Close()
+				}
+				return nil, ctx.Err()
 			}
+			logWith(h.option.logger, LevelInfo, "starling: retry http request",
+				Field{"project", pid}, Field{"namespace", nid}, Field{"env", opt.env}, Field{"language", lang},
+				Field{"attempt", retryTimes}, Field{"error", err})
 			if resp != nil && resp.Body != nil {
 				resp.Body.Close()
 			}
@@ -239,27 +181,61 @@ func (h *httpFetcher) FetchVersion(ctx context.Context, pid, nid int64, lang str
 		}
 		break
 	}
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
-	var result struct {
-		Status int    `json:"status"`
-		Data   string `json:"data"`
+	return
+}
+
+// roundTrip sends the request through the fetcher-level interceptors first
+// and then the request-level ones, and records the metrics and span of the
+// given attempt.
+func (h *httpFetcher) roundTrip(req *http.Request, opt *option, attempt int, useBackupStorage bool) (resp *http.Response, err error) {
+	tracer := h.option.tracer
+	if tracer == nil {
+		tracer = opt.tracer
+	}
+	ctx, span := startSpan(req.Context(), tracer, spanHTTPAttempt,
+		Field{"attempt", attempt},
+		Field{"backup_storage", useBackupStorage},
+		Field{"path", req.URL.Path})
+	defer func() {
+		if resp != nil {
+			span.SetAttributes(Field{"status", resp.StatusCode})
+		}
+		endSpan(span, err)
+	}()
+	if tracer != nil {
+		tracer.Inject(ctx, req.Header)
 	}
-	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return
+	req = req.WithContext(ctx)
+
+	invoker := Invoker(func(req *http.Request) (*http.Response, error) {
+		begin := time.Now()
+		resp, err := h.httpClient.Do(req)
+		if h.option.metricer != nil {
+			tag := map[string]string{"status": "success"}
+			if err != nil {
+				tag["status"] = "failed"
+			}
+			elapsed := time.Now().Sub(begin)
+			h.option.metricer.EmitCounter(httpProxyMetricsKeyThroughput, 1, tag)
+			emitTimer(h.option.metricer, httpProxyMetricsKeyLatency, elapsed, tag)
+		}
+		return resp, err
+	})
+	invoker = chainInterceptors(invoker, opt.interceptors...)
+	invoker = chainInterceptors(invoker, h.option.interceptors...)
+	resp, err = invoker(req)
+	if resp == nil && err == nil {
+		// A short-circuiting interceptor must return either a response or an error.
+		err = errNilResponse
 	}
-	if result.Status != 0 {
-		err = errors.New(result.Data)
-		return
+	if h.option.logger != nil {
+		h.option.logger.Debug("do http request: req=%s, resp=%s, err=%v",
+			describeRequest(req), describeResponse(resp), err)
 	}
-	ver, _ = strconv.ParseInt(opt.version, 10, 64)
-	rel = result.Data
-	return
+	return resp, err
 }
 
-func (h *httpFetcher) buildHTTPRequest(pid, nid int64, lang string, useBackupStorage, onlyVersion bool, opt *option) (*http.Request, error) {
+func (h *httpFetcher) buildHTTPRequest(ctx context.Context, pid, nid int64, lang string, useBackupStorage, onlyVersion bool, opt *option) (*http.Request, error) {
 	// Build http request and set the custom header.
 	var path string
 	pidStr, nidStr := strconv.FormatInt(pid, 10), strconv.FormatInt(nid, 10)
@@ -300,7 +276,7 @@ func (h *httpFetcher) buildHTTPRequest(pid, nid int64, lang string, useBackupSto
 		h.option.logger.Info("prepare sending http request: %s", reqUrl.String())
 	}
 
-	req, err := http.NewRequest(http.MethodGet, reqUrl.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqUrl.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -310,7 +286,7 @@ func (h *httpFetcher) buildHTTPRequest(pid, nid int64, lang string, useBackupSto
 		key = opt.appKey
 	}
 	if len(key) == 0 {
-		return nil, fmt.Errorf("no app-key given")
+		return nil, ErrNoAppKey
 	}
 	oper := h.option.operator
 	if len(oper) == 0 {
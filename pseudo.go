@@ -0,0 +1,251 @@
+package i18n
+
+import (
+	"context"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// PseudoAccented is the pseudo locale with the accented and expanded texts,
+	// which reveals the hard-coded strings, truncation and encoding bugs.
+	PseudoAccented = "en-XA"
+	// PseudoBidi is the pseudo locale with the texts mirrored right-to-left,
+	// which reveals the layout bugs of the RTL languages.
+	PseudoBidi = "ar-XB"
+
+	defaultPseudoBaseLanguage = "en"
+	defaultPseudoExpansion    = 0.3
+)
+
+var (
+	pseudoAccents = map[rune]rune{}
+	pseudoPadding = []string{"one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten"}
+)
+
+func init() {
+	from := []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	to := []rune("åƀçðéƒĝĥîĵķļɱñöþǫŕšţûṽŵẋýžÅƁÇÐÉƑĜĤÎĴĶĻṀÑÖÞǪŔŠŢÛṼŴẊÝŽ")
+	for i, r := range from {
+		pseudoAccents[r] = to[i]
+	}
+}
+
+// PseudoConfig configures how the pseudo locales are synthesized from the
+// package of the base language.
+type PseudoConfig struct {
+	// BaseLanguage is the language to synthesize from, default is "en".
+	BaseLanguage string
+	// Expansion is the ratio to lengthen the texts by, default is 0.3, and a
+	// negative value disables the expansion.
+	Expansion float64
+	// Prefix and Suffix mark the start and end of the texts to reveal the
+	// truncation and concatenation, default are "[" and "]".
+	Prefix, Suffix string
+	// NoMarkers disables the prefix and suffix.
+	NoMarkers bool
+}
+
+func (cfg PseudoConfig) normalize() PseudoConfig {
+	if len(cfg.BaseLanguage) == 0 {
+		cfg.BaseLanguage = defaultPseudoBaseLanguage
+	}
+	if cfg.Expansion == 0 {
+		cfg.Expansion = defaultPseudoExpansion
+	}
+	if len(cfg.Prefix) == 0 && len(cfg.Suffix) == 0 {
+		cfg.Prefix, cfg.Suffix = "[", "]"
+	}
+	if cfg.NoMarkers {
+		cfg.Prefix, cfg.Suffix = "", ""
+	}
+	return cfg
+}
+
+func isPseudoLocale(lang string) bool {
+	return strings.EqualFold(lang, PseudoAccented) || strings.EqualFold(lang, PseudoBidi)
+}
+
+// pseudoEntry is the pseudo package synthesized from a cached base package,
+// which is synthesized again once the base package is refreshed.
+type pseudoEntry struct {
+	base        *Package
+	cfg         PseudoConfig
+	left, right string
+	pkg         *Package
+}
+
+// getPseudoPackage synthesizes the package of the pseudo locale from the
+// package of the base language. The plural rules of the base language are used
+// since the plural options are copied from it.
+func (c *client) getPseudoPackage(ctx context.Context, o *option, lang string, cfg PseudoConfig, opts ...Option) (*Package, error) {
+	cfg = cfg.normalize()
+	base, err := c.loadPackage(ctx, o, cfg.BaseLanguage, opts...)
+	if err != nil {
+		return nil, err
+	}
+	o.language = lang
+	if len(o.pluralDefaultLang) == 0 {
+		o.pluralDefaultLang = cfg.BaseLanguage
+	}
+
+	key := o.cacheKey(lang)
+	if val, ok := c.pseudo.Load(key); ok {
+		e := val.(*pseudoEntry)
+		if e.base == base && e.cfg == cfg && e.left == o.leftDelimiter && e.right == o.rightDelimiter {
+			return e.pkg, nil
+		}
+	}
+	pkg := pseudoPackage(base, lang, cfg, o.leftDelimiter, o.rightDelimiter)
+	c.pseudo.Store(key, &pseudoEntry{base: base, cfg: cfg, left: o.leftDelimiter, right: o.rightDelimiter, pkg: pkg})
+	return pkg, nil
+}
+
+// pseudoPackage returns a new package with the pseudo texts of the base one.
+func pseudoPackage(base *Package, lang string, cfg PseudoConfig, left, right string) *Package {
+	pkg := &Package{
+		Version:        base.Version,
+		ReleaseVersion: base.ReleaseVersion,
+		Language:       lang,
+		Data:           make(map[string]string, len(base.Data)),
+	}
+	for k, v := range base.Data {
+		pkg.Data[k] = pseudoText(v, lang, cfg, left, right)
+	}
+	return pkg
+}
+
+// pseudoText transforms the literal text of the ICU message only, so that the
+// plural options, `#` and the variables are kept. The variables of the custom
+// delimiters and the HTML tags are kept as well. The message which fails to
+// parse is transformed as a plain text with the `{...}` kept.
+func pseudoText(raw, lang string, cfg PseudoConfig, left, right string) string {
+	t := &pseudoTransformer{
+		bidi:    strings.EqualFold(lang, PseudoBidi),
+		protect: [][2]string{{"<", ">"}},
+	}
+	if len(left) != 0 && len(right) != 0 && (left != defaultLeftDelimiter || right != defaultRightDelimiter) {
+		t.protect = append(t.protect, [2]string{left, right})
+	}
+
+	var b strings.Builder
+	writeText(&b, cfg.Prefix, false)
+	var length int
+	if nodes, err := parseMessage(raw); err == nil {
+		length = messageLength(nodes)
+		writeMessage(&b, t.nodes(nodes), false)
+	} else {
+		t.protect = append(t.protect, [2]string{"{", "}"})
+		length = utf8.RuneCountInString(raw)
+		b.WriteString(t.literal(raw))
+	}
+	if cfg.Expansion > 0 {
+		pad := int(math.Ceil(float64(length) * cfg.Expansion))
+		for i, n := 0, 0; n < pad; i++ {
+			word := pseudoPadding[i%len(pseudoPadding)]
+			b.WriteString(" " + word)
+			n += len(word) + 1
+		}
+	}
+	writeText(&b, cfg.Suffix, false)
+	return b.String()
+}
+
+// messageLength returns the number of the literal characters of the message,
+// counting the longest option of the plural and select arguments.
+func messageLength(nodes []msgNode) int {
+	n := 0
+	for _, node := range nodes {
+		switch node.kind {
+		case nodeText:
+			n += utf8.RuneCountInString(node.text)
+		case nodePlural, nodeSelectOrdinal, nodeSelect:
+			max := 0
+			for _, opt := range node.options {
+				if l := messageLength(opt.value); l > max {
+					max = l
+				}
+			}
+			n += max
+		}
+	}
+	return n
+}
+
+// pseudoTransformer transforms the literal texts into the pseudo ones.
+type pseudoTransformer struct {
+	bidi    bool
+	protect [][2]string // the delimiters of the segments to keep
+}
+
+func (t *pseudoTransformer) nodes(nodes []msgNode) []msgNode {
+	out := make([]msgNode, len(nodes))
+	for i, n := range nodes {
+		switch n.kind {
+		case nodeText:
+			n.text = t.literal(n.text)
+		case nodePlural, nodeSelectOrdinal, nodeSelect:
+			options := make([]msgOption, len(n.options))
+			for j, opt := range n.options {
+				options[j] = msgOption{selector: opt.selector, value: t.nodes(opt.value)}
+			}
+			n.options = options
+		}
+		out[i] = n
+	}
+	return out
+}
+
+// literal accents the letters, or wraps each word with the RTL override for
+// the bidi pseudo locale, and keeps the protected segments.
+func (t *pseudoTransformer) literal(s string) string {
+	var b strings.Builder
+	inWord := false
+	endWord := func() {
+		if inWord {
+			b.WriteString("\u202c\u200f") // PDF, RLM
+			inWord = false
+		}
+	}
+	for i := 0; i < len(s); {
+		if seg := t.protected(s[i:]); len(seg) != 0 {
+			endWord()
+			b.WriteString(seg)
+			i += len(seg)
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		if !t.bidi {
+			if a, ok := pseudoAccents[r]; ok {
+				r = a
+			}
+			b.WriteRune(r)
+			continue
+		}
+		if unicode.IsSpace(r) {
+			endWord()
+		} else if !inWord {
+			b.WriteString("\u200f\u202e") // RLM, RLO
+			inWord = true
+		}
+		b.WriteRune(r)
+	}
+	endWord()
+	return b.String()
+}
+
+// protected returns the protected segment at the start of s if any.
+func (t *pseudoTransformer) protected(s string) string {
+	for _, p := range t.protect {
+		if !strings.HasPrefix(s, p[0]) {
+			continue
+		}
+		if end := strings.Index(s[len(p[0]):], p[1]); end >= 0 {
+			return s[:len(p[0])+end+len(p[1])]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPseudoText(t *testing.T) {
+	cfg := PseudoConfig{}.normalize()
+	assert.Equal(t, "[Ĥéļļö <b>ŵöŕļð</b> one two]", pseudoText("Hello <b>world</b>", PseudoAccented, cfg, "", ""))
+	assert.Equal(t, "[{n, plural, one {# ðåý} other {# ðåýš}} one]", pseudoText("{n, plural, one {# day} other {# days}}", PseudoAccented, cfg, "", ""))
+	assert.Equal(t, "[Ĥî [[name]] one]", pseudoText("Hi [[name]]", PseudoAccented, cfg, "[[", "]]"))
+	assert.Equal(t, "[Ĥî {{name} one]", pseudoText("Hi {{name}", PseudoAccented, cfg, "", ""))
+
+	cfg = PseudoConfig{Expansion: -1, NoMarkers: true}.normalize()
+	assert.Equal(t, "\u200f\u202eHi\u202c\u200f {name}", pseudoText("Hi {name}", PseudoBidi, cfg, "", ""))
+	cfg = PseudoConfig{Expansion: 1, Prefix: "<<", Suffix: ">>"}.normalize()
+	assert.Equal(t, "<<Ĥî one>>", pseudoText("Hi", PseudoAccented, cfg, "", ""))
+}
+
+func TestPseudoLocale(t *testing.T) {
+	c, err := NewClient(1, 2, WithFetcher(&mockFetcher{}), WithPseudoLocale(PseudoConfig{}))
+	assert.Nil(t, err)
+	defer c.Shutdown(context.TODO())
+
+	pkg, err := c.GetPackage(context.TODO(), PseudoAccented)
+	assert.Nil(t, err)
+	assert.Equal(t, PseudoAccented, pkg.Language)
+	assert.Equal(t, "[ṽ1 one]", pkg.Data["key1"])
+	view, err := c.GetPackageView(context.TODO(), PseudoAccented)
+	assert.Nil(t, err)
+	view2, err := c.GetPackageView(context.TODO(), PseudoAccented)
+	assert.Nil(t, err)
+	assert.True(t, view.pkg == view2.pkg)
+
+	text, err := c.GetText(context.TODO(), PseudoAccented, "key5", WithPluralCount(2),
+		WithArguments(map[string]interface{}{"farm": "ByteDance"}))
+	assert.Nil(t, err)
+	assert.Equal(t, "[Î ĥåṽé 2 åþþļéš ƒŕöɱ ByteDance. one two]", text)
+	text, err = c.GetText(context.TODO(), PseudoBidi, "key3", WithLeftDelimiter("[["), WithRightDelimiter("]]"),
+		WithArguments(map[string]interface{}{"country": "China", "name": "Jack"}))
+	assert.Nil(t, err)
+	assert.Contains(t, text, "\u200f\u202eHe\u202c\u200f")
+	assert.Contains(t, text, "China")
+	assert.Contains(t, text, "Jack")
+
+	// Test the pseudo locale is fetched as is without the option.
+	c2, err := NewClient(1, 2, WithFetcher(&mockFetcher{}))
+	assert.Nil(t, err)
+	defer c2.Shutdown(context.TODO())
+	text, err = c2.GetText(context.TODO(), PseudoAccented, "key1")
+	assert.Nil(t, err)
+	assert.Equal(t, "v1", text)
+}
@@ -0,0 +1,162 @@
+package i18n
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// PreloadTarget specifies a package to load when creating the client. The
+// zero namespace and empty env mean the ones of the client.
+type PreloadTarget struct {
+	Language    string
+	NamespaceID int64
+	Env         string
+}
+
+// String returns the language if the namespace and env are not specified,
+// otherwise joins them with the language as `namespace/env/language`.
+func (t PreloadTarget) String() string {
+	if t.NamespaceID == 0 && len(t.Env) == 0 {
+		return t.Language
+	}
+	return strconv.FormatInt(t.NamespaceID, 10) + "/" + t.Env + "/" + t.Language
+}
+
+// options returns the request-level options to load the target.
+func (t PreloadTarget) options() []Option {
+	var opts []Option
+	if t.NamespaceID != 0 {
+		opts = append(opts, WithNamespaceID(t.NamespaceID))
+	}
+	if len(t.Env) != 0 {
+		opts = append(opts, WithEnv(t.Env))
+	}
+	return opts
+}
+
+// PreloadLanguages builds the preload targets of the given languages in the
+// namespace and env of the client.
+func PreloadLanguages(langs ...string) []PreloadTarget {
+	targets := make([]PreloadTarget, 0, len(langs))
+	for _, lang := range langs {
+		targets = append(targets, PreloadTarget{Language: lang})
+	}
+	return targets
+}
+
+// readiness tracks the loading state of the preload targets.
+type readiness struct {
+	mu      sync.Mutex
+	targets []PreloadTarget
+	errs    map[string]error
+	loaded  chan struct{}
+	once    sync.Once
+}
+
+func newReadiness(targets []PreloadTarget) *readiness {
+	r := &readiness{
+		targets: targets,
+		errs:    make(map[string]error),
+		loaded:  make(chan struct{}),
+	}
+	for _, t := range targets {
+		r.errs[t.String()] = ErrNotReady
+	}
+	if len(targets) == 0 { // nothing to wait for
+		r.once.Do(func() { close(r.loaded) })
+	}
+	return r
+}
+
+// load loads all the failed targets concurrently with the given function and
+// records the results. The first calling marks the initial loading finished.
+func (r *readiness) load(fn func(t PreloadTarget) error) {
+	r.mu.Lock()
+	var pending []PreloadTarget
+	for _, t := range r.targets {
+		if r.errs[t.String()] != nil {
+			pending = append(pending, t)
+		}
+	}
+	r.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, t := range pending {
+		wg.Add(1)
+		go func(t PreloadTarget) {
+			defer wg.Done()
+			err := fn(t)
+			r.mu.Lock()
+			r.errs[t.String()] = err
+			r.mu.Unlock()
+		}(t)
+	}
+	wg.Wait()
+
+	r.once.Do(func() { close(r.loaded) })
+}
+
+// err returns nil if all the targets are loaded, `ErrNotReady` if the
+// initial loading is not finished, otherwise a `BatchError` with the errors
+// of the failed targets.
+func (r *readiness) err() error {
+	select {
+	case <-r.loaded:
+	default:
+		return ErrNotReady
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	errs := make(map[string]error)
+	for k, err := range r.errs {
+		if err != nil {
+			errs[k] = err
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &BatchError{Errors: errs}
+}
+
+// pending reports whether any target failed in the finished loading, which
+// should be retried later.
+func (r *readiness) pending() bool {
+	select {
+	case <-r.loaded:
+	default:
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, err := range r.errs {
+		if err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// wait blocks until the initial loading finished or the context is done.
+func (r *readiness) wait(ctx context.Context) error {
+	select {
+	case <-r.loaded:
+		return r.err()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// NewReadinessHandler creates an `http.Handler` for the health endpoint, which
+// responds 200 if the client is ready, otherwise 503 with the error message.
+func NewReadinessHandler(c Client) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := c.Ready(); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	})
+}
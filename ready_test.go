@@ -0,0 +1,63 @@
+package i18n
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientWaitReady(t *testing.T) {
+	c, err := NewClient(1, 2, WithFetcher(&mockFetcher{}),
+		WithPreload(append(PreloadLanguages("en", "de"), PreloadTarget{Language: "ja", NamespaceID: 3, Env: EnvTest})...))
+	assert.NotNil(t, c)
+	assert.Nil(t, err)
+	defer c.Shutdown(context.TODO())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Nil(t, c.WaitReady(ctx))
+	assert.Nil(t, c.Ready())
+	for _, key := range []string{"1/2/normal/en", "1/2/normal/de", "1/3/test/ja"} {
+		_, exist := c.data.Load(key)
+		assert.True(t, exist, key)
+	}
+
+	rec := httptest.NewRecorder()
+	NewReadinessHandler(c).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestClientWaitReadyFailed(t *testing.T) {
+	c, err := NewClient(1, 2, WithFetcher(&mockFetcher{}), WithPreload(PreloadLanguages("en", "INVALID")...))
+	assert.NotNil(t, c)
+	assert.Nil(t, err)
+	defer c.Shutdown(context.TODO())
+
+	err = c.WaitReady(context.Background())
+	batchErr, ok := err.(*BatchError)
+	assert.True(t, ok)
+	assert.Equal(t, map[string]error{"INVALID": ErrBackToSourceFailed}, batchErr.Errors)
+	assert.Equal(t, err, c.Ready())
+	assert.Equal(t, "1 operations failed: INVALID: back to source to fetch data failed", err.Error())
+
+	rec := httptest.NewRecorder()
+	NewReadinessHandler(c).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestReadinessNotLoaded(t *testing.T) {
+	r := newReadiness(PreloadLanguages("en"))
+	assert.Equal(t, ErrNotReady, r.err())
+	assert.False(t, r.pending())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, r.wait(ctx))
+
+	// The readiness without targets is ready at once.
+	assert.Nil(t, newReadiness(nil).err())
+}
@@ -0,0 +1,138 @@
+package i18n
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	redactedMask      = "***"
+	maxRedactDerived  = 1024
+	minRedactSecretLn = 4
+)
+
+// jwtRegexp matches the auth token created by `CreateAuthToken`.
+var jwtRegexp = regexp.MustCompile(`[A-Za-z0-9_-]+=*\.[A-Za-z0-9_-]+=*\.[0-9a-fA-F]{64}`)
+
+// redactLogger formats the log content and redacts the auth tokens, the app
+// keys and the operators of the client before writing it to the underlying
+// logger.
+type redactLogger struct {
+	logger  Logger
+	secrets []string
+	// derived caches the wrappers with more secrets, so that the requests
+	// with the same app key and operator share a single wrapper.
+	derived sync.Map // string -> *redactLogger
+	size    int32
+}
+
+// withRedaction wraps the logger with the redaction layer of the app keys and
+// operators, the empty and too short ones which may match the normal content
+// are ignored. The wrapped logger is reused if it redacts all of them already.
+func withRedaction(l Logger, secrets ...string) Logger {
+	if l == nil {
+		return nil
+	}
+	r, ok := l.(*redactLogger)
+	if !ok {
+		r = &redactLogger{logger: l}
+	}
+	var missing []string
+next:
+	for _, secret := range secrets {
+		if len(secret) < minRedactSecretLn {
+			continue
+		}
+		for _, s := range r.secrets {
+			if s == secret {
+				continue next
+			}
+		}
+		for _, s := range missing {
+			if s == secret {
+				continue next
+			}
+		}
+		missing = append(missing, secret)
+	}
+	if len(missing) == 0 {
+		return r
+	}
+	key := strings.Join(missing, "\x00")
+	if d, ok := r.derived.Load(key); ok {
+		return d.(*redactLogger)
+	}
+	d := &redactLogger{logger: r.logger, secrets: append(r.secrets[:len(r.secrets):len(r.secrets)], missing...)}
+	if atomic.LoadInt32(&r.size) >= maxRedactDerived {
+		return d
+	}
+	if v, loaded := r.derived.LoadOrStore(key, d); loaded {
+		return v.(*redactLogger)
+	}
+	atomic.AddInt32(&r.size, 1)
+	return d
+}
+
+// redact returns the text with the auth tokens and secrets masked.
+func (l *redactLogger) redact(s string) string {
+	s = jwtRegexp.ReplaceAllString(s, redactedMask)
+	for _, secret := range l.secrets {
+		s = strings.Replace(s, secret, redactedMask, -1)
+	}
+	return s
+}
+
+// Debug implements the `Logger` interface.
+func (l *redactLogger) Debug(format string, v ...interface{}) {
+	l.logger.Debug("%s", l.redact(fmt.Sprintf(format, v...)))
+}
+
+// Info implements the `Logger` interface.
+func (l *redactLogger) Info(format string, v ...interface{}) {
+	l.logger.Info("%s", l.redact(fmt.Sprintf(format, v...)))
+}
+
+// Warn implements the `Logger` interface.
+func (l *redactLogger) Warn(format string, v ...interface{}) {
+	l.logger.Warn("%s", l.redact(fmt.Sprintf(format, v...)))
+}
+
+// Error implements the `Logger` interface.
+func (l *redactLogger) Error(format string, v ...interface{}) {
+	l.logger.Error("%s", l.redact(fmt.Sprintf(format, v...)))
+}
+
+// Log implements the `StructuredLogger` interface.
+func (l *redactLogger) Log(level Level, msg string, fields ...Field) {
+	redacted := make([]Field, len(fields))
+	for i, f := range fields {
+		redacted[i] = f
+		if s, ok := f.Value.(string); ok {
+			redacted[i].Value = l.redact(s)
+		} else if e, ok := f.Value.(error); ok && e != nil {
+			redacted[i].Value = l.redact(e.Error())
+		}
+	}
+	logWith(l.logger, level, l.redact(msg), redacted...)
+}
+
+// String summarizes the request-level option without the credentials, which
+// is used in the log content instead of dumping the whole struct.
+func (o *option) String() string {
+	if o == nil {
+		return "<nil>"
+	}
+	ver := o.version
+	if len(ver) == 0 {
+		ver = "latest"
+	}
+	return "project=" + strconv.FormatInt(o.projectID, 10) +
+		" namespace=" + strconv.FormatInt(o.namespaceID, 10) +
+		" env=" + o.env +
+		" lang=" + o.language +
+		" version=" + ver
+}
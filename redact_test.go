@@ -0,0 +1,56 @@
+package i18n
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordLogger struct {
+	lines []string
+}
+
+func (l *recordLogger) Debug(format string, v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf("[DEBUG]"+format, v...))
+}
+
+func (l *recordLogger) Info(format string, v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf("[INFO]"+format, v...))
+}
+
+func (l *recordLogger) Warn(format string, v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf("[WARN]"+format, v...))
+}
+
+func (l *recordLogger) Error(format string, v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf("[ERROR]"+format, v...))
+}
+
+func TestRedactLogger(t *testing.T) {
+	rec := &recordLogger{}
+	base := withRedaction(rec)
+	l := withRedaction(base, "redact-app-key-0001", "operator-eve")
+	assert.Equal(t, l, withRedaction(l, "redact-app-key-0001", ""))
+	// The wrappers of the same secrets are created once.
+	assert.True(t, l == withRedaction(base, "redact-app-key-0001", "operator-eve"))
+	assert.Nil(t, withRedaction(nil))
+
+	token := CreateAuthToken(1, 2, "redact-app-key-0001", "operator-eve")
+	l.Debug("token=%s", token)
+	l.Info("key=%s operator=%s", "redact-app-key-0001", "operator-eve")
+	l.Error("option: %v", &option{appKey: "redact-app-key-0001", projectID: 1, namespaceID: 2, env: EnvTest})
+	// The too short secrets are ignored, which may mask the normal content.
+	withRedaction(rec, "abc", "").Info("abc is not a secret")
+	// The app keys of the other clients are not redacted by this one.
+	withRedaction(rec, "redact-app-key-0002").Warn("keys=%s,%s", "redact-app-key-0001", "redact-app-key-0002")
+	withRedaction(l, "redact-app-key-0002").Warn("keys=%s,%s", "redact-app-key-0001", "redact-app-key-0002")
+	assert.Equal(t, []string{
+		"[DEBUG]token=***",
+		"[INFO]key=*** operator=***",
+		"[ERROR]option: project=1 namespace=2 env=test lang= version=latest",
+		"[INFO]abc is not a secret",
+		"[WARN]keys=redact-app-key-0001,***",
+		"[WARN]keys=***,***",
+	}, rec.lines)
+}
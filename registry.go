@@ -0,0 +1,262 @@
+package i18n
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// Registry manages the clients of many projects and namespaces, which share a
+// single fetcher, local cache and background refresher instead of creating a
+// standalone client for each of them. The app key of each project should be
+// given when registering it rather than creating the registry.
+type Registry struct {
+	core    *client
+	mu      sync.RWMutex
+	handles map[string]*handle
+}
+
+// NewRegistry creates a registry with the global options shared by all the
+// registered projects and namespaces.
+func NewRegistry(opts ...Option) *Registry {
+	r := &Registry{
+		core:    newClient(0, 0, opts...),
+		handles: make(map[string]*handle),
+	}
+	r.core.onRefresh = r.refresh
+	return r
+}
+
+// Register adds a project and namespace to the registry with its own options,
+// such as the app key and preload packages, and returns a lightweight client
+// handle of it. It fails if the registry is shutdown or the project and
+// namespace is already registered.
+func (r *Registry) Register(pid, nid int64, opts ...Option) (Client, error) {
+	if pid <= 0 || nid <= 0 {
+		return nil, ErrInvalidParams
+	}
+	h := &handle{
+		core:        r.core,
+		projectID:   pid,
+		namespaceID: nid,
+		remove:      func() { r.Remove(pid, nid) },
+	}
+	h.options.Store(append([]Option{WithProjectID(pid), WithNamespaceID(nid), withOwner(h)}, opts...))
+	o := option{}
+	for _, f := range opts {
+		f(&o)
+	}
+	h.ready = newReadiness(o.preload)
+
+	key := registryKey(pid, nid)
+	r.mu.Lock()
+	if r.core.isClosed() {
+		r.mu.Unlock()
+		return nil, ErrClientClosed
+	}
+	if _, exist := r.handles[key]; exist {
+		r.mu.Unlock()
+		return nil, ErrAlreadyRegistered
+	}
+	r.handles[key] = h
+	r.mu.Unlock()
+
+	go h.ready.load(h.preload)
+	return h, nil
+}
+
+// Client returns the client handle of the registered project and namespace.
+func (r *Registry) Client(pid, nid int64) (Client, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	h, ok := r.handles[registryKey(pid, nid)]
+	if !ok {
+		return nil, false
+	}
+	return h, true
+}
+
+// Remove removes the project and namespace from the registry and purges its
+// cached packages, including the ones loaded by its handle with the request
+// level project or namespace. The removed handle returns `ErrClientClosed`
+// afterwards.
+func (r *Registry) Remove(pid, nid int64) bool {
+	key := registryKey(pid, nid)
+	r.mu.Lock()
+	h, ok := r.handles[key]
+	delete(r.handles, key)
+	r.mu.Unlock()
+	if !ok {
+		return false
+	}
+	atomic.StoreInt32(&h.closed, 1)
+	r.core.data.Range(func(k, v interface{}) bool {
+		if e, ok := v.(*cacheEntry); ok && (e.owner == h || e.projectID == pid && e.namespaceID == nid) {
+			r.core.data.Delete(k)
+		}
+		return true
+	})
+	return true
+}
+
+// AddOption adds the global options shared by all the registered projects.
+func (r *Registry) AddOption(opts ...Option) {
+	r.core.AddOption(opts...)
+}
+
+// Shutdown shuts down the shared client, see `Client.Shutdown` for details.
+func (r *Registry) Shutdown(ctx context.Context) error {
+	return r.core.Shutdown(ctx)
+}
+
+// refresh retries the failed preload packages of the handles.
+func (r *Registry) refresh(ctx context.Context) {
+	r.mu.RLock()
+	handles := make([]*handle, 0, len(r.handles))
+	for _, h := range r.handles {
+		handles = append(handles, h)
+	}
+	r.mu.RUnlock()
+	for _, h := range handles {
+		if h.ready.pending() {
+			h.ready.load(h.preload)
+		}
+	}
+}
+
+// withOwner marks the requests of the handle, so that the packages it loads
+// are purged when it is removed.
+func withOwner(h *handle) Option {
+	return func(o *option) {
+		o.owner = h
+	}
+}
+
+func registryKey(pid, nid int64) string {
+	return strconv.FormatInt(pid, 10) + "/" + strconv.FormatInt(nid, 10)
+}
+
+// handle is the client of a project and namespace in the registry, which
+// delegates all the requests to the shared client with its own options.
+type handle struct {
+	core        *client
+	projectID   int64
+	namespaceID int64
+	options     atomic.Value // []Option, copy-on-write snapshot
+	mu          sync.Mutex
+	ready       *readiness
+	closed      int32
+	remove      func()
+}
+
+// requestOptions puts the options of the handle before the request-level ones.
+func (h *handle) requestOptions(opts []Option) ([]Option, error) {
+	if atomic.LoadInt32(&h.closed) == 1 {
+		return nil, ErrClientClosed
+	}
+	options := h.options.Load().([]Option)
+	return append(options[:len(options):len(options)], opts...), nil
+}
+
+// GetPackage implements the `Client` interface's method.
+func (h *handle) GetPackage(ctx context.Context, lang string, opts ...Option) (*Package, error) {
+	opts, err := h.requestOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return h.core.GetPackage(ctx, lang, opts...)
+}
+
+// GetPackageView implements the `Client` interface's method.
+func (h *handle) GetPackageView(ctx context.Context, lang string, opts ...Option) (PackageView, error) {
+	opts, err := h.requestOptions(opts)
+	if err != nil {
+		return PackageView{}, err
+	}
+	return h.core.GetPackageView(ctx, lang, opts...)
+}
+
+// GetPackages implements the `Client` interface's method.
+func (h *handle) GetPackages(ctx context.Context, langs []string, opts ...Option) (map[string]*Package, error) {
+	opts, err := h.requestOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return h.core.GetPackages(ctx, langs, opts...)
+}
+
+// GetText implements the `Client` interface's method.
+func (h *handle) GetText(ctx context.Context, lang, key string, opts ...Option) (string, error) {
+	opts, err := h.requestOptions(opts)
+	if err != nil {
+		return "", err
+	}
+	return h.core.GetText(ctx, lang, key, opts...)
+}
+
+// ExplainText implements the `Client` interface's method.
+func (h *handle) ExplainText(ctx context.Context, lang, key string, opts ...Option) (*TextResult, error) {
+	opts, err := h.requestOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return h.core.ExplainText(ctx, lang, key, opts...)
+}
+
+// GetRichText implements the `Client` interface's method.
+func (h *handle) GetRichText(ctx context.Context, lang, key string, tags []string, opts ...Option) (RichText, error) {
+	opts, err := h.requestOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return h.core.GetRichText(ctx, lang, key, tags, opts...)
+}
+
+// GetTexts implements the `Client` interface's method.
+func (h *handle) GetTexts(ctx context.Context, lang string, keys []string, opts ...Option) (map[string]string, error) {
+	opts, err := h.requestOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return h.core.GetTexts(ctx, lang, keys, opts...)
+}
+
+// AddOption implements the `Client` interface's method, the options only
+// take effect on the requests of this handle.
+func (h *handle) AddOption(opts ...Option) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	old := h.options.Load().([]Option)
+	options := make([]Option, len(old), len(old)+len(opts))
+	copy(options, old)
+	h.options.Store(append(options, opts...))
+}
+
+// WaitReady implements the `Client` interface's method.
+func (h *handle) WaitReady(ctx context.Context) error {
+	return h.ready.wait(ctx)
+}
+
+// Ready implements the `Client` interface's method.
+func (h *handle) Ready() error {
+	return h.ready.err()
+}
+
+// Shutdown implements the `Client` interface's method, which removes the
+// project and namespace from the registry.
+func (h *handle) Shutdown(ctx context.Context) error {
+	h.remove()
+	return nil
+}
+
+func (h *handle) preload(t PreloadTarget) error {
+	opts, err := h.requestOptions(t.options())
+	if err != nil {
+		return err
+	}
+	o := op.get()
+	defer op.put(o)
+	_, err = h.core.getPackage(h.core.ctx, o, t.Language, opts...)
+	return err
+}
@@ -0,0 +1,101 @@
+package i18n
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// authFetcher returns the app key and project of the request as the text.
+type authFetcher struct {
+	mockFetcher
+}
+
+func (a *authFetcher) Fetch(ctx context.Context, pid, nid int64, lang string, opts ...Option) (*Package, error) {
+	o := &option{}
+	for _, f := range opts {
+		f(o)
+	}
+	pkg, err := a.mockFetcher.Fetch(ctx, pid, nid, lang, opts...)
+	if err != nil {
+		return nil, err
+	}
+	pkg.Data = map[string]string{"auth": strconv.FormatInt(pid, 10) + "/" + strconv.FormatInt(nid, 10) + ":" + o.appKey}
+	return pkg, nil
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(WithFetcher(&authFetcher{}))
+	defer r.Shutdown(context.TODO())
+
+	_, err := r.Register(0, 1)
+	assert.Equal(t, ErrInvalidParams, err)
+	c1, err := r.Register(1, 2, WithAppKey("app-key-1"), WithPreload(PreloadLanguages("en")...))
+	assert.Nil(t, err)
+	c2, err := r.Register(3, 4, WithAppKey("app-key-3"), WithEnv(EnvTest))
+	assert.Nil(t, err)
+	_, err = r.Register(1, 2)
+	assert.Equal(t, ErrAlreadyRegistered, err)
+	assert.Nil(t, c1.WaitReady(context.TODO()))
+	assert.Nil(t, c2.Ready())
+
+	text, err := c1.GetText(context.TODO(), "en", "auth")
+	assert.Nil(t, err)
+	assert.Equal(t, "1/2:app-key-1", text)
+	text, err = c2.GetText(context.TODO(), "en", "auth")
+	assert.Nil(t, err)
+	assert.Equal(t, "3/4:app-key-3", text)
+	text, err = c2.GetText(context.TODO(), "en", "auth", WithNamespaceID(5))
+	assert.Nil(t, err)
+	assert.Equal(t, "3/5:app-key-3", text)
+	// The package of another handle is never served without the own credentials.
+	text, err = c2.GetText(context.TODO(), "en", "auth", WithProjectID(1), WithNamespaceID(2), WithEnv(EnvNormal))
+	assert.Nil(t, err)
+	assert.Equal(t, "1/2:app-key-3", text)
+	_, exist := r.core.data.Load("3/4/test/en@3/4")
+	assert.True(t, exist)
+
+	// Test the handle level options and the refresher with the own app keys.
+	c1.AddOption(WithAppKey("app-key-2"))
+	r.core.refresh(context.TODO())
+	text, err = c1.GetText(context.TODO(), "en", "auth")
+	assert.Nil(t, err)
+	assert.Equal(t, "1/2:app-key-2", text)
+	text, err = c2.GetText(context.TODO(), "en", "auth")
+	assert.Nil(t, err)
+	assert.Equal(t, "3/4:app-key-3", text)
+
+	got, ok := r.Client(1, 2)
+	assert.True(t, ok)
+	assert.Equal(t, c1, got)
+
+	// Test removing the project.
+	assert.True(t, r.Remove(3, 4))
+	assert.False(t, r.Remove(3, 4))
+	_, ok = r.Client(3, 4)
+	assert.False(t, ok)
+	_, exist = r.core.data.Load("3/4/test/en@3/4")
+	assert.False(t, exist)
+	_, exist = r.core.data.Load("3/5/test/en@3/4")
+	assert.False(t, exist)
+	_, err = c2.GetText(context.TODO(), "en", "auth")
+	assert.Equal(t, ErrClientClosed, err)
+	_, err = c2.GetPackage(context.TODO(), "en")
+	assert.Equal(t, ErrClientClosed, err)
+
+	// Test the removed project can be registered again.
+	c2, err = r.Register(3, 4, WithAppKey("app-key-4"))
+	assert.Nil(t, err)
+	texts, err := c2.GetTexts(context.TODO(), "en", []string{"auth"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"auth": "3/4:app-key-4"}, texts)
+	assert.Nil(t, c2.Shutdown(context.TODO()))
+	_, ok = r.Client(3, 4)
+	assert.False(t, ok)
+
+	assert.Nil(t, r.Shutdown(context.TODO()))
+	_, err = r.Register(5, 6)
+	assert.Equal(t, ErrClientClosed, err)
+}
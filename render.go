@@ -0,0 +1,193 @@
+package i18n
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// choiceRegexp matches the plural, selectordinal and select arguments in a
+// message, which are rendered even if no plural count is given.
+var choiceRegexp = regexp.MustCompile(`{\s*\w+\s*,\s*(plural|selectordinal|select)\s*,`)
+
+// argMarkStart and argMarkEnd surround the index of a formatted argument in
+// the rendered rich text, which is expanded after the tags are parsed.
+const (
+	argMarkStart = "\ue000"
+	argMarkEnd   = "\ue001"
+)
+
+// messageRenderer renders a parsed message in one pass, so that the quoted
+// literals are never parsed again. The simple arguments are formatted by
+// `formatValue` if args is true, otherwise they are written back.
+type messageRenderer struct {
+	lang     string       // the language to format the count and arguments
+	rules    language.Tag // the language of the plural rules
+	loc      *time.Location
+	isolated bool // whether to wrap the arguments and counts in the directional isolates
+	count    interface{}
+	vars     map[string]interface{}
+	args     bool
+	// mark writes the marks of the formatted arguments instead, which are
+	// collected in marked.
+	mark   bool
+	marked []string
+}
+
+// parseFormat parses the raw text to be rendered in one pass. The nodes are
+// nil if the text is formatted by `processVars` instead, i.e. the custom
+// delimiters are used, or the text without any plural or select fails to
+// parse, which is kept for compatibility. The text without any plural, select
+// or argument to format is never parsed, so it is returned as it is.
+func parseFormat(o *option, raw string) ([]msgNode, error) {
+	choice := o.pluralCount != nil || choiceRegexp.MatchString(raw)
+	plain := len(o.arguments) == 0 || !strings.ContainsAny(raw, "{}'")
+	if customDelimiters(o.leftDelimiter, o.rightDelimiter) || !choice && plain {
+		return nil, nil
+	}
+	nodes, err := parseMessage(raw)
+	switch {
+	case err != nil && choice:
+		return nil, err
+	case err != nil:
+		return nil, nil
+	case o.pluralCount != nil && !hasChoice(nodes):
+		return nil, ErrInvalidICUFormat
+	}
+	return nodes, nil
+}
+
+// renderMessage renders the parsed message with the plural count and the
+// arguments of the option. If mark is true, the formatted arguments are
+// returned and their marks are written instead.
+func renderMessage(nodes []msgNode, lang string, o *option, mark bool) (string, []string, error) {
+	rules := o.pluralDefaultLang
+	if len(rules) == 0 {
+		rules = lang
+	}
+	r := &messageRenderer{
+		lang:     lang,
+		rules:    language.Make(rules),
+		loc:      o.timeZone,
+		isolated: o.bidiIsolation && LanguageDirection(lang) == DirectionRTL,
+		count:    o.pluralCount,
+		vars:     o.arguments,
+		args:     len(o.arguments) != 0,
+		mark:     mark,
+	}
+	var b strings.Builder
+	if err := r.render(&b, nodes, "", "#"); err != nil {
+		return "", nil, err
+	}
+	return b.String(), r.marked, nil
+}
+
+// customDelimiters reports whether the variables use the custom delimiters
+// rather than the ICU arguments.
+func customDelimiters(left, right string) bool {
+	return len(left) != 0 && left != defaultLeftDelimiter || len(right) != 0 && right != defaultRightDelimiter
+}
+
+func (r *messageRenderer) render(b *strings.Builder, nodes []msgNode, name, pound string) error {
+	for _, n := range nodes {
+		switch n.kind {
+		case nodeText:
+			b.WriteString(n.text)
+		case nodePound:
+			b.WriteString(pound)
+		case nodeArg:
+			if n.name == name && len(n.typ) == 0 {
+				// The legacy `{num}` in the options of `{num, plural, ...}`.
+				b.WriteString(pound)
+				continue
+			}
+			if !r.args {
+				writeMessage(b, []msgNode{n}, false)
+				continue
+			}
+			text, err := formatValue(r.lang, r.loc, r.vars, n.name, n.typ, n.style)
+			if err != nil {
+				return err
+			}
+			if r.isolated {
+				text = isolate(text)
+			}
+			r.writeArg(b, text)
+		case nodePlural, nodeSelectOrdinal:
+			count, ok := r.vars[n.name]
+			if !ok {
+				count = r.count
+			}
+			if count == nil {
+				return fmt.Errorf("%w %q for the plural count", ErrMissingArgument, n.name)
+			}
+			value, num, err := r.choosePlural(n, count)
+			if err != nil {
+				return err
+			}
+			text := formatCount(r.lang, num)
+			if r.isolated {
+				text = isolate(text)
+			}
+			if err := r.render(b, value, n.name, text); err != nil {
+				return err
+			}
+		case nodeSelect:
+			value, err := r.chooseSelect(n)
+			if err != nil {
+				return err
+			}
+			// The `#` in a select nested in a plural is still the count.
+			if err := r.render(b, value, name, pound); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeArg writes the formatted argument, or its mark if mark is true.
+func (r *messageRenderer) writeArg(b *strings.Builder, text string) {
+	if !r.mark {
+		b.WriteString(text)
+		return
+	}
+	b.WriteString(argMarkStart + strconv.Itoa(len(r.marked)) + argMarkEnd)
+	r.marked = append(r.marked, text)
+}
+
+// chooseSelect returns the option of the select argument matching the value
+// of the argument, or the other option.
+func (r *messageRenderer) chooseSelect(n msgNode) ([]msgNode, error) {
+	val, ok := r.vars[n.name]
+	var other []msgNode
+	for _, opt := range n.options {
+		if ok && opt.selector == fmt.Sprint(val) {
+			return opt.value, nil
+		}
+		if opt.selector == "other" {
+			other = opt.value
+		}
+	}
+	switch {
+	case other != nil:
+		return other, nil
+	case !ok:
+		return nil, fmt.Errorf("%w %q, and select misses the other option", ErrMissingArgument, n.name)
+	}
+	return nil, &FormatError{Pos: n.pos, Msg: fmt.Sprintf("select %q has no option for %q and misses the other option", n.name, fmt.Sprint(val))}
+}
+
+// hasChoice reports whether the message has a plural, selectordinal or select
+// argument.
+func hasChoice(nodes []msgNode) bool {
+	found := false
+	walkMessage(nodes, func(n msgNode) {
+		found = found || n.kind != nodeText && n.kind != nodeArg && n.kind != nodePound
+	})
+	return found
+}
@@ -0,0 +1,55 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectMessage(t *testing.T) {
+	c, err := NewClient(1, 2, WithFetcher(&mockFetcher{}), WithOverrides(NewStaticOverrides(Override{Texts: map[string]string{
+		"liked":  "{user} liked {gender, select, female {her} male {his} other {their}} photo",
+		"nested": "{gender, select, female {{n, plural, one {She has # friend} other {She has # friends}}} other {{n, plural, one {They have # friend} other {They have # friends}}}}",
+		"pound":  "{n, plural, one {{role, select, admin {# admin} other {# member}}} other {{role, select, admin {# admins} other {# members}}}}",
+		"strict": "{plan, select, free {Free} pro {Pro}}",
+		"flag":   "{enabled, select, true {on} false {off} other {unknown}}",
+	}})))
+	assert.Nil(t, err)
+	defer c.Shutdown(context.TODO())
+	ctx := context.TODO()
+
+	for _, item := range []struct {
+		key    string
+		count  interface{}
+		args   map[string]interface{}
+		result string
+	}{
+		{"liked", nil, map[string]interface{}{"user": "Ann", "gender": "female"}, "Ann liked her photo"},
+		{"liked", nil, map[string]interface{}{"user": "Bob", "gender": "male"}, "Bob liked his photo"},
+		{"liked", nil, map[string]interface{}{"user": "Sam"}, "Sam liked their photo"},
+		{"nested", 1, map[string]interface{}{"gender": "female"}, "She has 1 friend"},
+		{"nested", 3, map[string]interface{}{"gender": "other"}, "They have 3 friends"},
+		{"pound", 2, map[string]interface{}{"role": "admin"}, "2 admins"},
+		{"pound", 1, map[string]interface{}{"role": "guest"}, "1 member"},
+		{"strict", nil, map[string]interface{}{"plan": "pro"}, "Pro"},
+		{"flag", nil, map[string]interface{}{"enabled": true}, "on"},
+	} {
+		opts := []Option{WithArguments(item.args)}
+		if item.count != nil {
+			opts = append(opts, WithPluralCount(item.count))
+		}
+		text, err := c.GetText(ctx, "en", item.key, opts...)
+		assert.Nil(t, err, item.key)
+		assert.Equal(t, item.result, text, item)
+	}
+
+	_, err = c.GetText(ctx, "en", "strict")
+	assert.True(t, errors.Is(err, ErrMissingArgument))
+	assert.Contains(t, err.Error(), `"plan"`)
+	_, err = c.GetText(ctx, "en", "strict", WithArguments(map[string]interface{}{"plan": "team"}))
+	assert.True(t, errors.Is(err, ErrInvalidICUFormat))
+	_, err = c.GetText(ctx, "en", "nested", WithArguments(map[string]interface{}{"gender": "female"}))
+	assert.True(t, errors.Is(err, ErrMissingArgument))
+}
@@ -0,0 +1,222 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// richTagRegexp matches the opening, closing and self-closing tags without
+// attributes, e.g. `<link>`, `</link>` and `<br/>`.
+var richTagRegexp = regexp.MustCompile(`<(/?)([A-Za-z][\w.-]*)\s*(/?)>`)
+
+// RichNode is a node of the rich text, which is either a text or a tag with
+// its children.
+type RichNode struct {
+	Tag      string     `json:"tag,omitempty"`
+	Text     string     `json:"text,omitempty"`
+	Children []RichNode `json:"children,omitempty"`
+}
+
+// RichText is a message parsed into the text and tag nodes, e.g.
+// `Read the <link>terms</link>`.
+type RichText []RichNode
+
+// TagHandler renders a tag with its rendered children, e.g. wraps them in an
+// HTML link or the terminal escapes. The values of the arguments are HTML
+// escaped already, so the children can be wrapped in HTML as they are.
+type TagHandler func(children string) string
+
+// Render renders the rich text with the handlers of the tags, the children of
+// a tag without a handler are rendered as they are.
+func (t RichText) Render(handlers map[string]TagHandler) string {
+	return t.RenderText(handlers, nil)
+}
+
+// RenderText renders the rich text like `Render`, and the text nodes are
+// transformed by text first if it is not nil, e.g. `html.UnescapeString` for
+// the plain text or terminal output.
+func (t RichText) RenderText(handlers map[string]TagHandler, text func(string) string) string {
+	var b strings.Builder
+	t.render(&b, handlers, text)
+	return b.String()
+}
+
+func (t RichText) render(b *strings.Builder, handlers map[string]TagHandler, text func(string) string) {
+	for _, n := range t {
+		if len(n.Tag) == 0 {
+			if text != nil {
+				b.WriteString(text(n.Text))
+			} else {
+				b.WriteString(n.Text)
+			}
+			continue
+		}
+		handler, ok := handlers[n.Tag]
+		if !ok {
+			RichText(n.Children).render(b, handlers, text)
+			continue
+		}
+		b.WriteString(handler(RichText(n.Children).RenderText(handlers, text)))
+	}
+}
+
+// String returns the text without the tags.
+func (t RichText) String() string {
+	return t.Render(nil)
+}
+
+// GetRichText implements the `Client` interface's method. The tags are parsed
+// after the plural and select arguments are rendered, and the other arguments
+// are formatted in the text nodes, so their values are never parsed as tags.
+func (c *client) GetRichText(ctx context.Context, lang, key string, tags []string, opts ...Option) (RichText, error) {
+	o := op.get()
+	defer op.put(o)
+	layers, err := c.lookupLayers(ctx, o, lang, opts...)
+	if err != nil {
+		return nil, c.wrapError(o, lang, key, TextResult{}, err)
+	}
+	res, err := c.resolveRaw(ctx, o, layers, lang, key)
+	if err != nil {
+		return nil, c.wrapError(o, lang, key, res, err)
+	}
+	if res.Source == SourceMissing {
+		return RichText{{Text: res.Text}}, nil
+	}
+	text, err := c.formatRich(ctx, o, res.Text, lang, key, tags)
+	if err != nil {
+		return nil, c.wrapError(o, lang, key, res, err)
+	}
+	return text, nil
+}
+
+// formatRich renders the raw text with the marks of the arguments, parses the
+// tags and then expands the marks in the text nodes. The variables of the
+// custom delimiters are processed in the text nodes after the tags are parsed.
+func (c *client) formatRich(ctx context.Context, o *option, val, lang, key string, tags []string) (RichText, error) {
+	nodes, err := parseFormat(o, val)
+	if err != nil {
+		return nil, err
+	}
+	if nodes != nil {
+		_, span := startSpan(ctx, o.tracer, spanRenderMessage, Field{"key", key})
+		var args []string
+		val, args, err = renderMessage(nodes, lang, o, true)
+		endSpan(span, err)
+		if err != nil {
+			return nil, err
+		}
+		text, err := parseRichText(val, tags)
+		if err != nil {
+			return nil, err
+		}
+		if len(args) != 0 {
+			pairs := make([]string, 0, 2*len(args))
+			for i, arg := range args {
+				pairs = append(pairs, argMarkStart+strconv.Itoa(i)+argMarkEnd, arg)
+			}
+			expandArgs(text, strings.NewReplacer(pairs...))
+		}
+		return text, nil
+	}
+	if o.pluralCount != nil || choiceRegexp.MatchString(val) {
+		_, span := startSpan(ctx, o.tracer, spanProcessPlural, Field{"key", key})
+		val, err = c.processPlural(val, lang, o.pluralDefaultLang, o.pluralCount, o.bidiIsolation, o.arguments)
+		endSpan(span, err)
+		if err != nil {
+			return nil, err
+		}
+	}
+	text, err := parseRichText(val, tags)
+	if err != nil {
+		return nil, err
+	}
+	if len(o.arguments) != 0 {
+		_, span := startSpan(ctx, o.tracer, spanProcessVars, Field{"key", key})
+		err = c.formatRichText(text, lang, o)
+		endSpan(span, err)
+	}
+	return text, err
+}
+
+// expandArgs replaces the marks of the arguments in the text nodes.
+func expandArgs(text RichText, r *strings.Replacer) {
+	for i := range text {
+		if len(text[i].Tag) != 0 {
+			expandArgs(text[i].Children, r)
+			continue
+		}
+		text[i].Text = r.Replace(text[i].Text)
+	}
+}
+
+func (c *client) formatRichText(text RichText, lang string, o *option) (err error) {
+	for i := range text {
+		if len(text[i].Tag) != 0 {
+			if err = c.formatRichText(text[i].Children, lang, o); err != nil {
+				return
+			}
+			continue
+		}
+		text[i].Text, err = c.processVars(text[i].Text, lang, o.timeZone, o.bidiIsolation, o.arguments, o.leftDelimiter, o.rightDelimiter)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// parseRichText parses the tags in the text, which must be in the allowed
+// tags and balanced. The text like `a < b` which is not a tag is kept.
+func parseRichText(text string, tags []string) (RichText, error) {
+	allowed := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		allowed[tag] = true
+	}
+	type frame struct {
+		tag   string
+		pos   int
+		nodes RichText
+	}
+	stack := []frame{{}}
+	appendNode := func(n RichNode) {
+		top := &stack[len(stack)-1]
+		top.nodes = append(top.nodes, n)
+	}
+	last := 0
+	for _, m := range richTagRegexp.FindAllStringSubmatchIndex(text, -1) {
+		closing, name, selfClosing := m[3] > m[2], text[m[4]:m[5]], m[7] > m[6]
+		if !allowed[name] {
+			return nil, fmt.Errorf("%w: tag <%s> is not allowed at %d", ErrInvalidRichText, name, m[0])
+		}
+		if m[0] > last {
+			appendNode(RichNode{Text: text[last:m[0]]})
+		}
+		last = m[1]
+		switch {
+		case selfClosing && !closing:
+			appendNode(RichNode{Tag: name})
+		case closing && !selfClosing:
+			top := stack[len(stack)-1]
+			if top.tag != name {
+				return nil, fmt.Errorf("%w: unmatched closing tag </%s> at %d", ErrInvalidRichText, name, m[0])
+			}
+			stack = stack[:len(stack)-1]
+			appendNode(RichNode{Tag: name, Children: top.nodes})
+		case !closing:
+			stack = append(stack, frame{tag: name, pos: m[0]})
+		default:
+			return nil, fmt.Errorf("%w: invalid tag at %d", ErrInvalidRichText, m[0])
+		}
+	}
+	if len(stack) > 1 {
+		top := stack[len(stack)-1]
+		return nil, fmt.Errorf("%w: unclosed tag <%s> at %d", ErrInvalidRichText, top.tag, top.pos)
+	}
+	if last < len(text) {
+		appendNode(RichNode{Text: text[last:]})
+	}
+	return stack[0].nodes, nil
+}
@@ -0,0 +1,80 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"html"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRichText(t *testing.T) {
+	text, err := parseRichText("Read the <link>terms of <b>use</b></link>.<br/> 1 < 2", []string{"link", "b", "br"})
+	assert.Nil(t, err)
+	assert.Equal(t, RichText{
+		{Text: "Read the "},
+		{Tag: "link", Children: []RichNode{{Text: "terms of "}, {Tag: "b", Children: []RichNode{{Text: "use"}}}}},
+		{Text: "."},
+		{Tag: "br"},
+		{Text: " 1 < 2"},
+	}, text)
+	assert.Equal(t, "Read the terms of use. 1 < 2", text.String())
+	assert.Equal(t, "Read the [terms of *use*](/terms).\n 1 < 2", text.Render(map[string]TagHandler{
+		"link": func(s string) string { return "[" + s + "](/terms)" },
+		"b":    func(s string) string { return "*" + s + "*" },
+		"br":   func(string) string { return "\n" },
+	}))
+
+	for _, item := range []struct {
+		text, msg string
+	}{
+		{"<script>x</script>", "invalid rich text: tag <script> is not allowed at 0"},
+		{"a <b>x</link>", "invalid rich text: unmatched closing tag </link> at 6"},
+		{"a <b>x", "invalid rich text: unclosed tag <b> at 2"},
+		{"a </b/>", "invalid rich text: invalid tag at 2"},
+	} {
+		_, err := parseRichText(item.text, []string{"b", "link"})
+		assert.True(t, errors.Is(err, ErrInvalidRichText))
+		assert.EqualError(t, err, item.msg)
+	}
+}
+
+func TestGetRichText(t *testing.T) {
+	c, err := NewClient(1, 2, WithFetcher(&mockFetcher{}), WithOverrides(NewStaticOverrides(Override{Texts: map[string]string{
+		"terms":  "{n, plural, one {<b>#</b> file} other {<b>#</b> files}} shared by <link>{user}</link>",
+		"nested": "Hi {user}, see <link>terms of <b>use</b></link>",
+	}})))
+	assert.Nil(t, err)
+	defer c.Shutdown(context.TODO())
+
+	text, err := c.GetRichText(context.TODO(), "en", "terms", []string{"b", "link"},
+		WithPluralCount(1200), WithArguments(map[string]interface{}{"user": "<b>Eve</b>"}))
+	assert.Nil(t, err)
+	assert.Equal(t, `<strong>1,200</strong> files shared by <a href="/u">&lt;b&gt;Eve&lt;/b&gt;</a>`, text.Render(map[string]TagHandler{
+		"b":    func(s string) string { return "<strong>" + s + "</strong>" },
+		"link": func(s string) string { return `<a href="/u">` + s + "</a>" },
+	}))
+
+	// The arguments outside the tags are escaped, and the nested tags are
+	// never escaped twice.
+	args := WithArguments(map[string]interface{}{"user": "<script>Tom & Jerry</script>"})
+	text, err = c.GetRichText(context.TODO(), "en", "nested", []string{"b", "link"}, args)
+	assert.Nil(t, err)
+	handlers := map[string]TagHandler{
+		"b":    func(s string) string { return "<b>" + s + "</b>" },
+		"link": func(s string) string { return `<a href="/terms">` + s + "</a>" },
+	}
+	assert.Equal(t, `Hi &lt;script&gt;Tom &amp; Jerry&lt;/script&gt;, see <a href="/terms">terms of <b>use</b></a>`,
+		text.Render(handlers))
+	// The other formats unescape the text nodes.
+	assert.Equal(t, "Hi <script>Tom & Jerry</script>, see \x1b[4mterms of \x1b[1muse\x1b[0m\x1b[0m", text.RenderText(map[string]TagHandler{
+		"b":    func(s string) string { return "\x1b[1m" + s + "\x1b[0m" },
+		"link": func(s string) string { return "\x1b[4m" + s + "\x1b[0m" },
+	}, html.UnescapeString))
+
+	_, err = c.GetRichText(context.TODO(), "en", "terms", []string{"b"}, WithPluralCount(1))
+	assert.True(t, errors.Is(err, ErrInvalidRichText))
+	_, err = c.GetRichText(context.TODO(), "en", "not-exist-key", nil)
+	assert.True(t, errors.Is(err, ErrKeyNotExist))
+}
@@ -0,0 +1,88 @@
+package i18n
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	spanGetPackage    = "starling.getPackage"
+	spanCacheLookup   = "starling.cache.lookup"
+	spanSingleflight  = "starling.singleflight.wait"
+	spanHTTPAttempt   = "starling.http.attempt"
+	spanProcessPlural = "starling.processPlural"
+	spanProcessVars   = "starling.processVars"
+	spanRenderMessage = "starling.renderMessage"
+)
+
+// Tracer abstracts the distributed tracing facility to record the spans of the
+// SDK internal procedures, such as fetching, cache lookup and formatting. It
+// can be adapted to OpenTelemetry or any other tracing system by wrapping its
+// tracer and propagator, so the SDK does not depend on any of them.
+type Tracer interface {
+	// Start creates a span with the given name as a child of the span in the
+	// context, and returns the context which carries the new span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+
+	// Inject propagates the trace context into the outbound request headers.
+	Inject(ctx context.Context, header http.Header)
+}
+
+// Span is a single traced procedure created by the `Tracer`.
+type Span interface {
+	// SetAttributes attaches the key-value fields to the span.
+	SetAttributes(fields ...Field)
+
+	// RecordError marks the span as failed with the given error.
+	RecordError(err error)
+
+	// End completes the span.
+	End()
+}
+
+// NoopTracer creates a tracer which records nothing and is used by default.
+func NoopTracer() Tracer {
+	return noopTracer{}
+}
+
+type noopTracer struct{}
+
+// Start implements the `Tracer` interface.
+func (noopTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+// Inject implements the `Tracer` interface.
+func (noopTracer) Inject(ctx context.Context, header http.Header) {}
+
+type noopSpan struct{}
+
+// SetAttributes implements the `Span` interface.
+func (noopSpan) SetAttributes(fields ...Field) {}
+
+// RecordError implements the `Span` interface.
+func (noopSpan) RecordError(err error) {}
+
+// End implements the `Span` interface.
+func (noopSpan) End() {}
+
+// startSpan starts a span with the tracer which may be nil, and attaches the
+// given fields to it.
+func startSpan(ctx context.Context, t Tracer, name string, fields ...Field) (context.Context, Span) {
+	if t == nil {
+		return ctx, noopSpan{}
+	}
+	ctx, span := t.Start(ctx, name)
+	if len(fields) != 0 {
+		span.SetAttributes(fields...)
+	}
+	return ctx, span
+}
+
+// endSpan records the error if any and ends the span.
+func endSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
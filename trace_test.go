@@ -0,0 +1,99 @@
+package i18n
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordTracer struct {
+	mu    sync.Mutex
+	spans []*recordSpan
+}
+
+type recordSpan struct {
+	name   string
+	parent string
+	attrs  map[string]interface{}
+	err    error
+	ended  bool
+}
+
+type spanKey struct{}
+
+func (t *recordTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	s := &recordSpan{name: name, attrs: make(map[string]interface{})}
+	if parent, ok := ctx.Value(spanKey{}).(*recordSpan); ok {
+		s.parent = parent.name
+	}
+	t.mu.Lock()
+	t.spans = append(t.spans, s)
+	t.mu.Unlock()
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+func (t *recordTracer) Inject(ctx context.Context, header http.Header) {
+	if s, ok := ctx.Value(spanKey{}).(*recordSpan); ok {
+		header.Set("X-Trace-Span", s.name)
+	}
+}
+
+func (s *recordSpan) SetAttributes(fields ...Field) {
+	for _, f := range fields {
+		s.attrs[f.Key] = f.Value
+	}
+}
+
+func (s *recordSpan) RecordError(err error) { s.err = err }
+
+func (s *recordSpan) End() { s.ended = true }
+
+func TestClientTracer(t *testing.T) {
+	tracer := &recordTracer{}
+	c, err := NewClient(1, 2, WithFetcher(&mockFetcher{}), WithTracer(tracer))
+	assert.NotNil(t, c)
+	assert.Nil(t, err)
+	defer c.Shutdown(context.TODO())
+
+	_, err = c.GetText(context.TODO(), "en", "key5",
+		WithPluralCount(10),
+		WithArguments(map[string]interface{}{"farm": "ByteDance"}))
+	assert.Nil(t, err)
+
+	var names []string
+	for _, s := range tracer.spans {
+		names = append(names, s.name+"<"+s.parent)
+		assert.True(t, s.ended, s.name)
+	}
+	assert.Equal(t, []string{
+		spanGetPackage + "<",
+		spanCacheLookup + "<" + spanGetPackage,
+		spanSingleflight + "<" + spanGetPackage,
+		spanRenderMessage + "<",
+	}, names)
+	assert.Equal(t, false, tracer.spans[1].attrs["hit"])
+	assert.Equal(t, false, tracer.spans[2].attrs["shared"])
+}
+
+func TestHttpFetcherTracer(t *testing.T) {
+	var header http.Header
+	srv := newPackageServer(t, func(r *http.Request) { header = r.Header })
+	defer srv.Close()
+
+	tracer := &recordTracer{}
+	p := NewHttpFetcher(
+		WithHTTPDomain(strings.TrimPrefix(srv.URL, "http://")),
+		WithAppKey("app12345"),
+		WithTracer(tracer))
+	_, err := p.Fetch(context.TODO(), 1, 2, "en")
+	assert.Nil(t, err)
+	assert.Equal(t, spanHTTPAttempt, header.Get("X-Trace-Span"))
+	assert.Equal(t, 1, len(tracer.spans))
+	assert.Equal(t, 1, tracer.spans[0].attrs["attempt"])
+	assert.Equal(t, false, tracer.spans[0].attrs["backup_storage"])
+	assert.Equal(t, http.StatusOK, tracer.spans[0].attrs["status"])
+}
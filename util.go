@@ -1,11 +1,14 @@
 package i18n
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"os"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 // Logger abstracts the procedure to record internal states with 4 levels.
@@ -23,35 +26,158 @@ type Logger interface {
 	Error(format string, v ...interface{})
 }
 
+// Level is the severity of a log, the logs below the minimum level of a
+// logger will be dropped.
+type Level int
+
+const (
+	// LevelDebug is used for the detailed internal states of each request.
+	LevelDebug Level = iota
+	// LevelInfo is used for the normal but notable states.
+	LevelInfo
+	// LevelWarn is used for the failures which can be recovered.
+	LevelWarn
+	// LevelError is used for the failures which affect the result.
+	LevelError
+	// LevelOff disables all the logs.
+	LevelOff
+)
+
+// String returns the upper case name of the level.
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	case LevelOff:
+		return "OFF"
+	}
+	return "LEVEL(" + strconv.Itoa(int(l)) + ")"
+}
+
+// Field is a key-value pair attached to a structured log, such as project,
+// namespace, env, language, key, version and attempt.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// StructuredLogger extends the `Logger` with the ability to record a message
+// with key-value fields instead of formatting them into the message.
+type StructuredLogger interface {
+	Logger
+
+	// Log writes a log with the given level, message and fields.
+	Log(level Level, msg string, fields ...Field)
+}
+
+// logWith writes the message with fields to the logger. It uses the `Log`
+// method if the logger is a `StructuredLogger`, otherwise the fields are
+// appended to the message as `key=value` pairs.
+func logWith(l Logger, level Level, msg string, fields ...Field) {
+	if l == nil {
+		return
+	}
+	if sl, ok := l.(StructuredLogger); ok {
+		sl.Log(level, msg, fields...)
+		return
+	}
+	logLevel(l, level, "%s", formatFields(msg, fields))
+}
+
+// logLevel dispatches the printf-style log to the method of the given level.
+func logLevel(l Logger, level Level, format string, v ...interface{}) {
+	switch level {
+	case LevelDebug:
+		l.Debug(format, v...)
+	case LevelInfo:
+		l.Info(format, v...)
+	case LevelWarn:
+		l.Warn(format, v...)
+	case LevelError:
+		l.Error(format, v...)
+	}
+}
+
+// formatFields appends the fields to the message as `key=value` pairs.
+func formatFields(msg string, fields []Field) string {
+	if len(fields) == 0 {
+		return msg
+	}
+	var b strings.Builder
+	b.WriteString(msg)
+	for _, f := range fields {
+		b.WriteString(" ")
+		b.WriteString(f.Key)
+		b.WriteString("=")
+		b.WriteString(fmt.Sprint(f.Value))
+	}
+	return b.String()
+}
+
 // DefaultLogger creates a logger which is used by default with the standard log
-// library and output the data to stdout.
+// library and output the data with info level or higher to stdout.
 func DefaultLogger() Logger {
+	return DefaultLoggerWithLevel(LevelInfo)
+}
+
+// DefaultLoggerWithLevel creates a default logger which drops the logs below
+// the given minimum level.
+func DefaultLoggerWithLevel(level Level) Logger {
 	l := log.New(os.Stdout, "starling-goclient: ", log.Ldate|log.Lmicroseconds|log.Lmsgprefix)
-	return &logService{l}
+	return &logService{logger: l, level: level}
+}
+
+// NewStdLogger creates a structured logger which writes to the given standard
+// library logger and drops the logs below the given minimum level.
+func NewStdLogger(logger *log.Logger, level Level) StructuredLogger {
+	return &logService{logger: logger, level: level}
 }
 
 type logService struct {
 	logger *log.Logger
+	level  Level
 }
 
 // Debug implements the `Logger` interface.
 func (l *logService) Debug(format string, v ...interface{}) {
-	l.logger.Printf("[DEBUG]"+format, v...)
+	if l.level <= LevelDebug {
+		l.logger.Printf("[DEBUG]"+format, v...)
+	}
 }
 
 // Info implements the `Logger` interface.
 func (l *logService) Info(format string, v ...interface{}) {
-	l.logger.Printf("[INFO]"+format, v...)
+	if l.level <= LevelInfo {
+		l.logger.Printf("[INFO]"+format, v...)
+	}
 }
 
 // Warn implements the `Logger` interface.
 func (l *logService) Warn(format string, v ...interface{}) {
-	l.logger.Printf("[WARN]"+format, v...)
+	if l.level <= LevelWarn {
+		l.logger.Printf("[WARN]"+format, v...)
+	}
 }
 
 // Error implements the `Logger` interface.
 func (l *logService) Error(format string, v ...interface{}) {
-	l.logger.Printf("[ERROR]"+format, v...)
+	if l.level <= LevelError {
+		l.logger.Printf("[ERROR]"+format, v...)
+	}
+}
+
+// Log implements the `StructuredLogger` interface.
+func (l *logService) Log(level Level, msg string, fields ...Field) {
+	if level < l.level || level >= LevelOff {
+		return
+	}
+	l.logger.Print("[" + level.String() + "]" + formatFields(msg, fields))
 }
 
 // Metricer provides the metrics facility to monitor the current service with
@@ -65,7 +191,7 @@ type Metricer interface {
 // library and output the data to stderr.
 func DefaultMetricer() Metricer {
 	l := log.New(os.Stderr, "starling-goclient-metrics: ", log.Ldate|log.Lmicroseconds)
-	return &metricsService{&logService{l}}
+	return &metricsService{&logService{logger: l}}
 }
 
 type metricsService struct {
@@ -74,21 +200,41 @@ type metricsService struct {
 
 // EmitCounter implements the `Metricer` interface.
 func (m *metricsService) EmitCounter(name string, value interface{}, tags map[string]string) {
-	if tags == nil {
-		tags = make(map[string]string)
-	}
-	tags["lang"] = "go"
-	tags["version"] = SDKVersion
-	tags["platform"] = Platform
-	tags["ip"] = LocalIP
+	m.emit(name, value, tags)
+}
+
+// EmitTimer implements the `ExtendedMetricer` interface.
+func (m *metricsService) EmitTimer(name string, d time.Duration, tags map[string]string) {
+	m.emit(name, d, tags)
+}
 
-	tagsArr := make([]string, 0, len(tags))
+// EmitHistogram implements the `ExtendedMetricer` interface.
+func (m *metricsService) EmitHistogram(name string, value float64, tags map[string]string) {
+	m.emit(name, value, tags)
+}
+
+// EmitGauge implements the `ExtendedMetricer` interface.
+func (m *metricsService) EmitGauge(name string, value float64, tags map[string]string) {
+	m.emit(name, value, tags)
+}
+
+func (m *metricsService) emit(name string, value interface{}, tags map[string]string) {
+	tagsArr := make([]string, 0, len(tags)+4)
 	for k, v := range tags {
 		tagsArr = append(tagsArr, k+":"+v)
 	}
+	tagsArr = append(tagsArr, "lang:go", "version:"+SDKVersion, "platform:"+Platform, "ip:"+LocalIP)
 	m.logger.Info("%s=%v[%s]", name, value, strings.Join(tagsArr, ","))
 }
 
+// Flusher is implemented by the facilities which buffer the data, such as the
+// logger, metricer, tracer and fetcher, and they are flushed when the client
+// is shutdown.
+type Flusher interface {
+	// Flush writes out all the buffered data until the context is done.
+	Flush(ctx context.Context) error
+}
+
 // Group represents a class of work and forms a namespace in which
 // units of work can be executed with duplicate suppression.
 type Group struct {
@@ -108,6 +254,13 @@ type call struct {
 // time. If a duplicate comes in, the duplicate caller waits for the
 // original to complete and receives the same results.
 func (g *Group) Do(key string, fn func() (interface{}, error)) (interface{}, error) {
+	val, err, _ := g.do(key, fn)
+	return val, err
+}
+
+// do works as `Do` and also reports whether the result is shared with the
+// other callers of the same key.
+func (g *Group) do(key string, fn func() (interface{}, error)) (interface{}, error, bool) {
 	g.mu.Lock()
 	if g.m == nil {
 		g.m = make(map[string]*call)
@@ -115,7 +268,7 @@ func (g *Group) Do(key string, fn func() (interface{}, error)) (interface{}, err
 	if c, ok := g.m[key]; ok {
 		g.mu.Unlock()
 		c.wg.Wait()
-		return c.val, c.err
+		return c.val, c.err, true
 	}
 	c := new(call)
 	c.wg.Add(1)
@@ -129,7 +282,7 @@ func (g *Group) Do(key string, fn func() (interface{}, error)) (interface{}, err
 	delete(g.m, key)
 	g.mu.Unlock()
 
-	return c.val, c.err
+	return c.val, c.err, false
 }
 
 func buildCacheKey(pid, nid int64, env, lang string) string {
@@ -1,7 +1,9 @@
 package i18n
 
 import (
+	"bytes"
 	"fmt"
+	"log"
 	"net"
 	"testing"
 	"time"
@@ -17,6 +19,24 @@ func TestLogger(t *testing.T) {
 	l.Error("test %s", "error")
 }
 
+func TestLeveledLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewStdLogger(log.New(&buf, "", 0), LevelWarn)
+	l.Debug("test %s", "debug")
+	l.Info("test %s", "info")
+	l.Warn("test %s", "warn")
+	l.Log(LevelInfo, "structured info", Field{"key", "k1"})
+	l.Log(LevelError, "structured error", Field{"key", "k1"}, Field{"attempt", 2})
+	assert.Equal(t, "[WARN]test warn\n[ERROR]structured error key=k1 attempt=2\n", buf.String())
+
+	// Test the fields fallback of the printf-style logger.
+	rec := &recordLogger{}
+	logWith(rec, LevelInfo, "msg", Field{"project", 1}, Field{"language", "en"})
+	logWith(nil, LevelInfo, "msg")
+	assert.Equal(t, []string{"[INFO]msg project=1 language=en"}, rec.lines)
+	assert.Equal(t, "ERROR", LevelError.String())
+}
+
 func TestMetricer(t *testing.T) {
 	m := DefaultMetricer()
 